@@ -2,24 +2,106 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/x0rium/compareNames/matcher"
+	"github.com/x0rium/compareNames/matcher/translit"
+	"github.com/x0rium/compareNames/matcher/utils"
 	"github.com/x0rium/compareNames/middleware"
 )
 
 // RequestBody структура для запроса к API
 type RequestBody struct {
-	Name1        string             `json:"name1"`
-	Name2        string             `json:"name2"`
-	Attributes   matcher.Attributes `json:"attributes,omitempty"`
+	Name1        string                  `json:"name1"`
+	Name2        string                  `json:"name2"`
+	Name1Parts   *matcher.StructuredName `json:"name1_parts,omitempty"` // Первое имя по частям вместо name1
+	Name2Parts   *matcher.StructuredName `json:"name2_parts,omitempty"` // Второе имя по частям вместо name2
+	Attributes   matcher.Attributes      `json:"attributes,omitempty"`
+	Language     string                  `json:"language,omitempty"`
+	Config       *matcher.Config         `json:"config,omitempty"`
+	DisableCache bool                    `json:"disable_cache,omitempty"`
+}
+
+// Ограничения пакетного запроса
+const (
+	MaxBatchSize     = 50000                           // Максимальное количество пар в одном пакетном запросе
+	MaxBatchPairSize = 1024                            // Размер одной пары в теле запроса, байт, с учетом атрибутов
+	MaxBatchBodySize = MaxBatchSize * MaxBatchPairSize // Максимальный размер тела пакетного запроса, байт
+)
+
+// BatchRequestBody структура для пакетного запроса к API
+type BatchRequestBody struct {
+	Pairs        []matcher.NamePair `json:"pairs"`
+	Language     string             `json:"language,omitempty"`
 	Config       *matcher.Config    `json:"config,omitempty"`
 	DisableCache bool               `json:"disable_cache,omitempty"`
 }
 
+// BatchItem результат сравнения одной пары в пакетном ответе
+type BatchItem struct {
+	Index  int                  `json:"index"`
+	Result *matcher.MatchResult `json:"result,omitempty"`
+	Error  string               `json:"error,omitempty"`
+}
+
+// BatchResponse структура ответа пакетного запроса
+type BatchResponse struct {
+	Results []BatchItem `json:"results"`
+}
+
+// Ограничения поиска по списку кандидатов
+const (
+	MaxSearchCandidates    = 100000                                       // Максимальное количество кандидатов в одном запросе
+	MaxSearchCandidateSize = 256                                          // Размер одного кандидата в теле запроса, байт
+	MaxSearchBodySize      = MaxSearchCandidates * MaxSearchCandidateSize // Максимальный размер тела запроса поиска, байт
+	DefaultSearchK         = 10                                           // Количество возвращаемых кандидатов по умолчанию
+)
+
+// SearchRequestBody структура запроса поиска имени в списке кандидатов
+type SearchRequestBody struct {
+	Query        string          `json:"query"`
+	Candidates   []string        `json:"candidates"`
+	K            int             `json:"k,omitempty"`
+	MinScore     int             `json:"min_score,omitempty"`
+	Language     string          `json:"language,omitempty"`
+	Config       *matcher.Config `json:"config,omitempty"`
+	DisableCache bool            `json:"disable_cache,omitempty"`
+}
+
+// SearchResponse структура ответа поиска
+type SearchResponse struct {
+	Matches []matcher.Candidate `json:"matches"`
+}
+
+// DictionaryRequestBody структура запроса на добавление записей в словарь имен
+type DictionaryRequestBody struct {
+	Entries []utils.DictionaryEntry `json:"entries"`
+}
+
+// DictionaryResponse структура ответа со записями словаря имен
+type DictionaryResponse struct {
+	Entries []utils.DictionaryEntry `json:"entries"`
+}
+
+// CognatesRequestBody структура запроса на добавление групп межъязыковых соответствий имен
+type CognatesRequestBody struct {
+	Clusters []utils.CognateCluster `json:"clusters"`
+}
+
+// CognatesResponse структура ответа с группами межъязыковых соответствий имен
+type CognatesResponse struct {
+	Clusters []utils.CognateCluster `json:"clusters"`
+}
+
+// sharedMatcher общий экземпляр для запросов с конфигурацией по умолчанию.
+// Кэш результатов и вариаций имен сохраняется между запросами
+var sharedMatcher = matcher.NewNameMatcher(nil)
+
 // ErrorResponse структура для ответа с ошибкой
 type ErrorResponse struct {
 	Error string `json:"error"`
@@ -41,31 +123,30 @@ func MatchNamesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Проверяем обязательные поля
-	if requestBody.Name1 == "" || requestBody.Name2 == "" {
-		sendErrorResponse(w, "Both name1 and name2 are required", http.StatusBadRequest)
+	// Проверяем обязательные поля: каждое имя задается строкой или по частям
+	name1 := matcher.NameInput{Text: requestBody.Name1, Parts: requestBody.Name1Parts}
+	name2 := matcher.NameInput{Text: requestBody.Name2, Parts: requestBody.Name2Parts}
+	if name1.IsEmpty() || name2.IsEmpty() {
+		sendErrorResponse(w, "Both name1 (or name1_parts) and name2 (or name2_parts) are required", http.StatusBadRequest)
 		return
 	}
 
-	// Настраиваем конфигурацию
-	config := requestBody.Config
-	if config == nil {
-		defaultConfig := matcher.DefaultConfig()
-		config = &defaultConfig
-	}
-
-	// Устанавливаем кэширование, если указано в запросе
-	if requestBody.DisableCache {
-		config.EnableCaching = false
+	if !translit.IsKnownLanguage(requestBody.Language) {
+		sendErrorResponse(w, "Unsupported language: "+requestBody.Language, http.StatusBadRequest)
+		return
 	}
 
-	// Выполняем сравнение имен через оригинальную функцию
-	result := matcher.MatchNames(
-		requestBody.Name1,
-		requestBody.Name2,
+	// Выполняем сравнение имен
+	result, err := matcherFor(requestBody.Config, requestBody.Language, requestBody.DisableCache).MatchInputs(
+		r.Context(),
+		name1,
+		name2,
 		requestBody.Attributes,
-		config,
 	)
+	if err != nil {
+		sendErrorResponse(w, "Request cancelled: "+err.Error(), http.StatusRequestTimeout)
+		return
+	}
 
 	// Отправляем ответ
 	w.Header().Set("Content-Type", "application/json")
@@ -77,6 +158,260 @@ func MatchNamesHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// MatchNamesBatchHandler обработчик для /api/match_names/batch
+func MatchNamesBatchHandler(w http.ResponseWriter, r *http.Request) {
+	// Проверяем метод запроса
+	if r.Method != http.MethodPost {
+		sendErrorResponse(w, "Method not allowed, use POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Парсим тело запроса; размер тела ограничен, чтобы не разбирать пакет сверх MaxBatchSize пар
+	var requestBody BatchRequestBody
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxBatchBodySize))
+	if err := decoder.Decode(&requestBody); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			sendErrorResponse(w, fmt.Sprintf("Request body too large, maximum is %d bytes", MaxBatchBodySize), http.StatusRequestEntityTooLarge)
+			return
+		}
+		sendErrorResponse(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Проверяем размер пакета
+	if len(requestBody.Pairs) == 0 {
+		sendErrorResponse(w, "At least one pair is required", http.StatusBadRequest)
+		return
+	}
+	if len(requestBody.Pairs) > MaxBatchSize {
+		sendErrorResponse(w, fmt.Sprintf("Too many pairs, maximum is %d", MaxBatchSize), http.StatusBadRequest)
+		return
+	}
+
+	if !translit.IsKnownLanguage(requestBody.Language) {
+		sendErrorResponse(w, "Unsupported language: "+requestBody.Language, http.StatusBadRequest)
+		return
+	}
+
+	// Сравниваем все пары с общей конфигурацией
+	results := matcherFor(requestBody.Config, requestBody.Language, requestBody.DisableCache).MatchBatch(r.Context(), requestBody.Pairs, 0)
+
+	response := BatchResponse{Results: make([]BatchItem, len(results))}
+	for i := range results {
+		response.Results[i].Index = i
+		if results[i].Err != nil {
+			response.Results[i].Error = results[i].Err.Error()
+		} else {
+			response.Results[i].Result = &results[i].Result
+		}
+	}
+
+	// Отправляем ответ
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// SearchHandler обработчик для /api/search
+func SearchHandler(w http.ResponseWriter, r *http.Request) {
+	// Проверяем метод запроса
+	if r.Method != http.MethodPost {
+		sendErrorResponse(w, "Method not allowed, use POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Парсим тело запроса; размер тела ограничен, чтобы не разбирать список сверх MaxSearchCandidates кандидатов
+	var requestBody SearchRequestBody
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxSearchBodySize))
+	if err := decoder.Decode(&requestBody); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			sendErrorResponse(w, fmt.Sprintf("Request body too large, maximum is %d bytes", MaxSearchBodySize), http.StatusRequestEntityTooLarge)
+			return
+		}
+		sendErrorResponse(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Проверяем обязательные поля
+	if requestBody.Query == "" || len(requestBody.Candidates) == 0 {
+		sendErrorResponse(w, "Both query and candidates are required", http.StatusBadRequest)
+		return
+	}
+	if len(requestBody.Candidates) > MaxSearchCandidates {
+		sendErrorResponse(w, fmt.Sprintf("Too many candidates, maximum is %d", MaxSearchCandidates), http.StatusBadRequest)
+		return
+	}
+
+	if !translit.IsKnownLanguage(requestBody.Language) {
+		sendErrorResponse(w, "Unsupported language: "+requestBody.Language, http.StatusBadRequest)
+		return
+	}
+
+	k := requestBody.K
+	if k <= 0 {
+		k = DefaultSearchK
+	}
+
+	// Ищем лучших кандидатов
+	matches, err := matcherFor(requestBody.Config, requestBody.Language, requestBody.DisableCache).FindBestMatches(
+		r.Context(),
+		requestBody.Query,
+		requestBody.Candidates,
+		k,
+		requestBody.MinScore,
+	)
+	if err != nil {
+		sendErrorResponse(w, "Request cancelled: "+err.Error(), http.StatusRequestTimeout)
+		return
+	}
+
+	// Отправляем ответ
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(SearchResponse{Matches: matches}); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// NicknamesHandler обработчик для /api/dictionary/nicknames.
+// GET возвращает записи словаря имен (с параметром name - только записи с этим именем или формой),
+// POST добавляет записи во время работы и возвращает их в объединенном виде
+func NicknamesHandler(w http.ResponseWriter, r *http.Request) {
+	dictionary := utils.Dictionary()
+
+	var entries []utils.DictionaryEntry
+	switch r.Method {
+	case http.MethodGet:
+		if name := r.URL.Query().Get("name"); name != "" {
+			entries = dictionary.Lookup(name)
+		} else {
+			entries = dictionary.Entries()
+		}
+
+	case http.MethodPost:
+		var requestBody DictionaryRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			sendErrorResponse(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(requestBody.Entries) == 0 {
+			sendErrorResponse(w, "At least one entry is required", http.StatusBadRequest)
+			return
+		}
+		if err := dictionary.Add(requestBody.Entries...); err != nil {
+			sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		for _, entry := range requestBody.Entries {
+			for _, merged := range dictionary.Lookup(strings.TrimSpace(entry.Name)) {
+				if merged.Name == strings.ToLower(strings.TrimSpace(entry.Name)) {
+					entries = append(entries, merged)
+				}
+			}
+		}
+
+	default:
+		sendErrorResponse(w, "Method not allowed, use GET or POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if entries == nil {
+		entries = []utils.DictionaryEntry{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(DictionaryResponse{Entries: entries}); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// CognatesHandler обработчик для /api/dictionary/cognates.
+// GET возвращает группы межъязыковых соответствий (с параметром name - группы, в которые входит имя),
+// POST добавляет группы во время работы сервера и возвращает группы с добавленными формами
+func CognatesHandler(w http.ResponseWriter, r *http.Request) {
+	dictionary := utils.Dictionary()
+
+	var clusters []utils.CognateCluster
+	switch r.Method {
+	case http.MethodGet:
+		clusters = dictionary.Cognates(r.URL.Query().Get("name"))
+
+	case http.MethodPost:
+		var requestBody CognatesRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			sendErrorResponse(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(requestBody.Clusters) == 0 {
+			sendErrorResponse(w, "At least one cluster is required", http.StatusBadRequest)
+			return
+		}
+		if err := dictionary.AddCognates(requestBody.Clusters...); err != nil {
+			sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// Возвращаем группы, в которые вошли добавленные формы
+		for _, cluster := range requestBody.Clusters {
+			for _, form := range cluster.Forms {
+				if strings.TrimSpace(form) != "" {
+					clusters = append(clusters, dictionary.Cognates(strings.TrimSpace(form))...)
+					break
+				}
+			}
+		}
+
+	default:
+		sendErrorResponse(w, "Method not allowed, use GET or POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if clusters == nil {
+		clusters = []utils.CognateCluster{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(CognatesResponse{Clusters: clusters}); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// matcherFor возвращает экземпляр NameMatcher для запроса.
+// Запросы без собственной конфигурации и языка обслуживаются общим экземпляром
+func matcherFor(config *matcher.Config, language string, disableCache bool) *matcher.NameMatcher {
+	if config == nil && language == "" && !disableCache {
+		return sharedMatcher
+	}
+
+	// Настраиваем конфигурацию
+	cfg := matcher.DefaultConfig()
+	if config != nil {
+		cfg = *config
+	}
+
+	// Язык из запроса имеет приоритет над подсказкой в конфигурации
+	if language != "" {
+		cfg.LanguageHint = language
+	}
+
+	// Отключаем кэширование, если указано в запросе
+	if disableCache {
+		cfg.EnableCaching = false
+	}
+
+	return matcher.NewNameMatcher(&cfg)
+}
+
 // HealthCheckHandler обработчик для проверки работоспособности API
 func HealthCheckHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -106,6 +441,16 @@ func SetupRoutes() http.Handler {
 	// API endpoint для сравнения имен
 	router.HandleFunc("/api/match_names", MatchNamesHandler).Methods("POST")
 
+	// API endpoint для пакетного сравнения имен
+	router.HandleFunc("/api/match_names/batch", MatchNamesBatchHandler).Methods("POST")
+
+	// API endpoint для поиска имени в списке кандидатов
+	router.HandleFunc("/api/search", SearchHandler).Methods("POST")
+
+	// API endpoint для просмотра и пополнения словаря имен
+	router.HandleFunc("/api/dictionary/nicknames", NicknamesHandler).Methods("GET", "POST")
+	router.HandleFunc("/api/dictionary/cognates", CognatesHandler).Methods("GET", "POST")
+
 	// Endpoint для проверки работоспособности API
 	router.HandleFunc("/health", HealthCheckHandler).Methods("GET")
 
@@ -6,13 +6,17 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/x0rium/compareNames/api"
 	"github.com/x0rium/compareNames/matcher"
+	"github.com/x0rium/compareNames/matcher/translit"
+	"github.com/x0rium/compareNames/matcher/utils"
 )
 
 // TestCase представляет собой тестовый случай для API
@@ -228,3 +232,501 @@ func TestHealthCheck(t *testing.T) {
 		t.Errorf("Неожиданный формат ответа: %s", body)
 	}
 }
+
+// postJSON отправляет POST-запрос с JSON-телом и декодирует JSON-ответ
+func postJSON(t *testing.T, url string, body interface{}, out interface{}) int {
+	t.Helper()
+
+	requestJSON, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("Ошибка при сериализации запроса: %v", err)
+	}
+
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(requestJSON))
+	if err != nil {
+		t.Fatalf("Ошибка при отправке запроса: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Ошибка при чтении ответа: %v", err)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			t.Fatalf("Ошибка при декодировании ответа: %v (%s)", err, respBody)
+		}
+	}
+
+	return resp.StatusCode
+}
+
+// TestMatchNamesCache проверяет, что повторный запрос обслуживается из кэша общего экземпляра
+func TestMatchNamesCache(t *testing.T) {
+	setupTestServer(t)
+	defer teardownTestServer(t)
+
+	apiURL := fmt.Sprintf("%s/api/match_names", baseURL)
+	requestBody := RequestBody{Name1: "Кэшева Тамара", Name2: "Kesheva Tamara"}
+
+	var first, second matcher.MatchResult
+	if code := postJSON(t, apiURL, requestBody, &first); code != http.StatusOK {
+		t.Fatalf("Неожиданный код ответа: %d", code)
+	}
+	if code := postJSON(t, apiURL, requestBody, &second); code != http.StatusOK {
+		t.Fatalf("Неожиданный код ответа: %d", code)
+	}
+
+	if first.FromCache {
+		t.Errorf("Первый запрос не должен обслуживаться из кэша")
+	}
+	if !second.FromCache {
+		t.Errorf("Повторный запрос должен обслуживаться из кэша")
+	}
+	if first.Score != second.Score || first.MatchType != second.MatchType {
+		t.Errorf("Результат из кэша отличается: %d/%s против %d/%s",
+			second.Score, second.MatchType, first.Score, first.MatchType)
+	}
+
+	// Запрос с отключенным кэшем всегда вычисляется заново
+	var uncached matcher.MatchResult
+	postJSON(t, apiURL, map[string]interface{}{
+		"name1":         requestBody.Name1,
+		"name2":         requestBody.Name2,
+		"disable_cache": true,
+	}, &uncached)
+	if uncached.FromCache {
+		t.Errorf("Запрос с disable_cache не должен обслуживаться из кэша")
+	}
+
+	// Разделители внутри значений атрибутов не должны давать одинаковых ключей кэша
+	var joined, split matcher.MatchResult
+	postJSON(t, apiURL, map[string]interface{}{
+		"name1":      requestBody.Name1,
+		"name2":      requestBody.Name2,
+		"attributes": map[string]interface{}{"document_number": map[string]string{"value1": "4510", "value2": "4510|654321"}},
+	}, &joined)
+	postJSON(t, apiURL, map[string]interface{}{
+		"name1":      requestBody.Name1,
+		"name2":      requestBody.Name2,
+		"attributes": map[string]interface{}{"document_number": map[string]string{"value1": "4510|4510", "value2": "654321"}},
+	}, &split)
+	if split.FromCache {
+		t.Errorf("Запрос с другими значениями атрибутов не должен обслуживаться из кэша")
+	}
+}
+
+// TestMatchNamesAttributes проверяет влияние дополнительных атрибутов на итоговую оценку
+func TestMatchNamesAttributes(t *testing.T) {
+	setupTestServer(t)
+	defer teardownTestServer(t)
+
+	apiURL := fmt.Sprintf("%s/api/match_names", baseURL)
+	name1, name2 := "Иванов Иван Петрович", "Иванов Иван Иванович"
+
+	var plain, same, different, flags matcher.MatchResult
+	postJSON(t, apiURL, RequestBody{Name1: name1, Name2: name2}, &plain)
+	postJSON(t, apiURL, map[string]interface{}{
+		"name1": name1,
+		"name2": name2,
+		"attributes": map[string]interface{}{
+			"birth_date": map[string]string{"value1": "1980-05-17", "value2": "17.05.1980"},
+		},
+	}, &same)
+	postJSON(t, apiURL, map[string]interface{}{
+		"name1": name1,
+		"name2": name2,
+		"attributes": map[string]interface{}{
+			"birth_date":      map[string]string{"value1": "1980-05-17", "value2": "1975-01-02"},
+			"document_number": map[string]string{"value1": "4510 123456", "value2": "4510 654321"},
+		},
+	}, &different)
+	// Логические значения равнозначны {"match": true|false}
+	postJSON(t, apiURL, map[string]interface{}{
+		"name1":      name1,
+		"name2":      name2,
+		"attributes": map[string]bool{"birth_date": true, "country": true},
+	}, &flags)
+
+	if same.Score <= plain.Score || same.AdditionalAttributesScore != 1 {
+		t.Errorf("Совпадающая дата рождения должна повышать оценку: %d (атрибуты %.2f) против %d",
+			same.Score, same.AdditionalAttributesScore, plain.Score)
+	}
+	if different.Score >= plain.Score || different.MatchType == "match" {
+		t.Errorf("Несовпадающие атрибуты должны понижать оценку: %d (%s) против %d",
+			different.Score, different.MatchType, plain.Score)
+	}
+	if flags.Score != same.Score {
+		t.Errorf("Атрибуты-признаки должны учитываться так же, как значения: %d против %d", flags.Score, same.Score)
+	}
+}
+
+// TestMatchNamesExplanation проверяет пояснение к оценке и его ориентацию для результатов из кэша
+func TestMatchNamesExplanation(t *testing.T) {
+	setupTestServer(t)
+	defer teardownTestServer(t)
+
+	apiURL := fmt.Sprintf("%s/api/match_names", baseURL)
+
+	var exact matcher.MatchResult
+	postJSON(t, apiURL, RequestBody{Name1: "Иван Иванов", Name2: "Иван Иванов"}, &exact)
+	if exact.Explanation != nil {
+		t.Errorf("Для точного совпадения пояснение не ожидается")
+	}
+
+	var permuted matcher.MatchResult
+	postJSON(t, apiURL, RequestBody{Name1: "Иванов Иван Петрович", Name2: "Петрович Иван Иванов"}, &permuted)
+	e := permuted.Explanation
+	if e == nil {
+		t.Fatalf("Ожидалось пояснение к оценке")
+	}
+	hasPermutation := false
+	for _, bonus := range e.Bonuses {
+		if bonus.Name == matcher.BonusPermutation {
+			hasPermutation = true
+		}
+	}
+	if !hasPermutation || !e.ScoreClamped || e.BaseScore <= 0 {
+		t.Errorf("Неожиданное пояснение: %+v", e)
+	}
+	if len(e.AlignedParts) != 3 || e.AlignedParts[0].Part1 != "Иванов" || e.AlignedParts[0].Part2 != "Иванов" ||
+		e.AlignedParts[0].Role != "surname" {
+		t.Errorf("Неожиданное сопоставление частей: %+v", e.AlignedParts)
+	}
+
+	// Результат из кэша для обратного порядка имен возвращается в порядке запроса
+	var direct, reversed matcher.MatchResult
+	postJSON(t, apiURL, RequestBody{Name1: "Кэшев Олег", Name2: "Keshev Oleg"}, &direct)
+	postJSON(t, apiURL, RequestBody{Name1: "Keshev Oleg", Name2: "Кэшев Олег"}, &reversed)
+	if !reversed.FromCache || reversed.Explanation == nil || len(reversed.Explanation.AlignedParts) == 0 {
+		t.Fatalf("Ожидался результат из кэша с пояснением")
+	}
+	if part := reversed.Explanation.AlignedParts[0]; part.Part1 != "Keshev" || part.Part2 != "Кэшев" {
+		t.Errorf("Стороны пояснения из кэша не соответствуют запросу: %+v", part)
+	}
+}
+
+// TestMatchNamesBestMatch проверяет лучшую пару вариантов и стандарты транслитерации
+func TestMatchNamesBestMatch(t *testing.T) {
+	setupTestServer(t)
+	defer teardownTestServer(t)
+
+	apiURL := fmt.Sprintf("%s/api/match_names", baseURL)
+
+	var result matcher.MatchResult
+	postJSON(t, apiURL, RequestBody{Name1: "Юрий", Name2: "Yuriy"}, &result)
+
+	if result.BestMatch1 != "yuriy" || result.BestMatch2 != "Yuriy" {
+		t.Errorf("Неожиданная лучшая пара: %q <-> %q", result.BestMatch1, result.BestMatch2)
+	}
+	if len(result.BestMatch1Standards) != 1 || result.BestMatch1Standards[0] != "bgnpcgn" {
+		t.Errorf("Ожидался стандарт bgnpcgn, получено %v", result.BestMatch1Standards)
+	}
+	if len(result.BestMatch2Standards) != 1 || result.BestMatch2Standards[0] != "original" {
+		t.Errorf("Ожидалось исходное написание, получено %v", result.BestMatch2Standards)
+	}
+}
+
+// TestMatchNamesTransliterationStandards проверяет, что набор стандартов транслитерации из конфигурации
+// влияет на варианты написания и оценку
+func TestMatchNamesTransliterationStandards(t *testing.T) {
+	setupTestServer(t)
+	defer teardownTestServer(t)
+
+	apiURL := fmt.Sprintf("%s/api/match_names", baseURL)
+	request := func(config matcher.Config) matcher.MatchResult {
+		var result matcher.MatchResult
+		postJSON(t, apiURL, map[string]interface{}{
+			"name1":  "Цой",
+			"name2":  "Tsoy",
+			"config": config,
+		}, &result)
+		return result
+	}
+
+	all := request(matcher.DefaultConfig())
+
+	gostConfig := matcher.DefaultConfig()
+	gostConfig.TransliterationStandards = []string{"gost", "unknown"}
+	gost := request(gostConfig)
+
+	disabledConfig := matcher.DefaultConfig()
+	disabledConfig.EnableTransliteration = false
+	disabled := request(disabledConfig)
+
+	if len(gost.BestMatch1Standards) != 1 || gost.BestMatch1Standards[0] != "gost" {
+		t.Errorf("Ожидался вариант по ГОСТ, получено %q %v", gost.BestMatch1, gost.BestMatch1Standards)
+	}
+	if gost.Score >= all.Score {
+		t.Errorf("Ограничение стандартов должно менять оценку: %d против %d", gost.Score, all.Score)
+	}
+	if disabled.BestMatch1 != "Цой" || disabled.Score >= gost.Score {
+		t.Errorf("Без транслитерации ожидалось исходное написание и меньшая оценка: %q, %d", disabled.BestMatch1, disabled.Score)
+	}
+}
+
+// TestMatchNamesLanguage проверяет подсказку языка в запросе
+func TestMatchNamesLanguage(t *testing.T) {
+	setupTestServer(t)
+	defer teardownTestServer(t)
+
+	apiURL := fmt.Sprintf("%s/api/match_names", baseURL)
+
+	var result matcher.MatchResult
+	status := postJSON(t, apiURL, map[string]interface{}{
+		"name1":    "Ольга Шевченко",
+		"name2":    "Olha Shevchenko",
+		"language": "uk",
+	}, &result)
+	if status != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получено %d", status)
+	}
+	hasUkrainian := false
+	for _, standard := range result.BestMatch1Standards {
+		if standard == "ukrainian" {
+			hasUkrainian = true
+		}
+	}
+	if !hasUkrainian {
+		t.Errorf("Ожидался вариант по украинскому стандарту, получено %q %v", result.BestMatch1, result.BestMatch1Standards)
+	}
+
+	var errorResponse map[string]string
+	status = postJSON(t, apiURL, map[string]interface{}{
+		"name1":    "Ольга Шевченко",
+		"name2":    "Olha Shevchenko",
+		"language": "xx",
+	}, &errorResponse)
+	if status != http.StatusBadRequest {
+		t.Errorf("Ожидался статус 400 для неизвестного языка, получено %d", status)
+	}
+}
+
+// TestMatchNamesHomoglyphs проверяет замену латинских букв-двойников в кириллических словах
+// и отметку смешения алфавитов
+func TestMatchNamesHomoglyphs(t *testing.T) {
+	setupTestServer(t)
+	defer teardownTestServer(t)
+
+	apiURL := fmt.Sprintf("%s/api/match_names", baseURL)
+
+	// "Ивaнoв" с латинскими "a" и "o"
+	var result matcher.MatchResult
+	postJSON(t, apiURL, RequestBody{Name1: "Ивaнoв Иван", Name2: "Иванов Иван"}, &result)
+	if !result.ExactMatch || !result.HomoglyphsNormalized || !result.MixedScriptSuspected {
+		t.Errorf("Ожидалось точное совпадение после замены двойников, получено %d (%s), normalized=%v, mixed=%v",
+			result.Score, result.MatchType, result.HomoglyphsNormalized, result.MixedScriptSuspected)
+	}
+
+	// Слова на разных алфавитах отмечаются, но не переписываются
+	var mixed matcher.MatchResult
+	postJSON(t, apiURL, RequestBody{Name1: "Ivanov Иван", Name2: "Ivanov Ivan"}, &mixed)
+	if mixed.HomoglyphsNormalized || !mixed.MixedScriptSuspected || mixed.MatchType != "match" {
+		t.Errorf("Ivanov Иван: получено %d (%s), normalized=%v, mixed=%v",
+			mixed.Score, mixed.MatchType, mixed.HomoglyphsNormalized, mixed.MixedScriptSuspected)
+	}
+
+	var plain matcher.MatchResult
+	postJSON(t, apiURL, RequestBody{Name1: "Иванов Иван", Name2: "Ivanov Ivan"}, &plain)
+	if plain.HomoglyphsNormalized || plain.MixedScriptSuspected {
+		t.Errorf("Имена на одном алфавите не должны отмечаться: normalized=%v, mixed=%v",
+			plain.HomoglyphsNormalized, plain.MixedScriptSuspected)
+	}
+
+	if normalized, ok := translit.NormalizeHomoglyphs("Сeргeй Ivanжov"); !ok || normalized != "Сергей Ivanжov" {
+		t.Errorf("NormalizeHomoglyphs: получено %q, %v", normalized, ok)
+	}
+	if got := utils.PreprocessName("Пeтрoв-Вoдкин"); got != "петров водкин" {
+		t.Errorf("PreprocessName: ожидалось %q, получено %q", "петров водкин", got)
+	}
+}
+
+// TestMatchNamesBatch проверяет пакетное сравнение: порядок результатов и ошибки по отдельным парам
+func TestMatchNamesBatch(t *testing.T) {
+	setupTestServer(t)
+	defer teardownTestServer(t)
+
+	testCases := LoadTestCases(t)
+
+	pairs := make([]RequestBody, 0, len(testCases)+1)
+	for _, tc := range testCases {
+		pairs = append(pairs, RequestBody{Name1: tc.Name1, Name2: tc.Name2})
+	}
+	// Пара без второго имени должна вернуть ошибку, не прерывая пакет
+	pairs = append(pairs, RequestBody{Name1: "Иван Иванов"})
+
+	var response struct {
+		Results []struct {
+			Index  int                  `json:"index"`
+			Result *matcher.MatchResult `json:"result"`
+			Error  string               `json:"error"`
+		} `json:"results"`
+	}
+
+	apiURL := fmt.Sprintf("%s/api/match_names/batch", baseURL)
+	if code := postJSON(t, apiURL, map[string]interface{}{"pairs": pairs}, &response); code != http.StatusOK {
+		t.Fatalf("Неожиданный код ответа: %d", code)
+	}
+
+	if len(response.Results) != len(pairs) {
+		t.Fatalf("Ожидалось %d результатов, получено %d", len(pairs), len(response.Results))
+	}
+
+	for i, tc := range testCases {
+		item := response.Results[i]
+		if item.Index != i {
+			t.Errorf("Результат %d пришел с индексом %d", i, item.Index)
+		}
+		if item.Result == nil {
+			t.Errorf("Нет результата для %s <-> %s: %s", tc.Name1, tc.Name2, item.Error)
+			continue
+		}
+		if item.Result.MatchType != tc.ExpectedMatchType {
+			t.Errorf("%s: ожидаемый тип совпадения %s, получен %s", tc.Name, tc.ExpectedMatchType, item.Result.MatchType)
+		}
+	}
+
+	last := response.Results[len(pairs)-1]
+	if last.Error == "" || last.Result != nil {
+		t.Errorf("Для пары без имени ожидалась ошибка, получено: %+v", last)
+	}
+
+	// Пустой пакет отклоняется целиком
+	if code := postJSON(t, apiURL, map[string]interface{}{"pairs": []RequestBody{}}, nil); code != http.StatusBadRequest {
+		t.Errorf("Для пустого пакета ожидался код %d, получен %d", http.StatusBadRequest, code)
+	}
+
+	// Тело больше MaxBatchBodySize не читается до конца
+	body := io.MultiReader(strings.NewReader(`{"pairs": [{"name1": "`), io.LimitReader(repeatReader('a'), api.MaxBatchBodySize))
+	recorder := httptest.NewRecorder()
+	api.MatchNamesBatchHandler(recorder, httptest.NewRequest(http.MethodPost, "/api/match_names/batch", body))
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Для слишком большого тела ожидался код %d, получен %d", http.StatusRequestEntityTooLarge, recorder.Code)
+	}
+}
+
+// repeatReader бесконечно повторяет один байт
+type repeatReader byte
+
+func (r repeatReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(r)
+	}
+	return len(p), nil
+}
+
+// TestMatchNamesStructured проверяет сравнение имен, переданных по частям
+func TestMatchNamesStructured(t *testing.T) {
+	setupTestServer(t)
+	defer teardownTestServer(t)
+
+	apiURL := fmt.Sprintf("%s/api/match_names", baseURL)
+	petrov := map[string]string{"last": "Петров", "first": "Иван"}
+
+	var free matcher.MatchResult
+	postJSON(t, apiURL, map[string]interface{}{"name1_parts": petrov, "name2": "Иван Петров"}, &free)
+	if free.MatchType != "match" && free.MatchType != "exact_match" {
+		t.Errorf("Петров Иван по частям / Иван Петров: ожидалось совпадение, получено %s (%d)", free.MatchType, free.Score)
+	}
+
+	// Фамилия сравнивается с фамилией: перестановка частей не спасает
+	var swapped matcher.MatchResult
+	postJSON(t, apiURL, map[string]interface{}{
+		"name1_parts": petrov,
+		"name2_parts": map[string]string{"last": "Иван", "first": "Петров"},
+	}, &swapped)
+	var swappedLatin matcher.MatchResult
+	postJSON(t, apiURL, map[string]interface{}{
+		"name1_parts": map[string]string{"last": "Petrov", "first": "Ivan"},
+		"name2_parts": map[string]string{"last": "Ivan", "first": "Petrov"},
+	}, &swappedLatin)
+	for _, result := range []matcher.MatchResult{swapped, swappedLatin} {
+		if result.MatchType != "no_match" {
+			t.Errorf("Части с другими ролями: ожидалось no_match, получено %s (%d)", result.MatchType, result.Score)
+		}
+	}
+
+	if code := postJSON(t, apiURL, map[string]interface{}{"name1_parts": map[string]string{}, "name2": "Иван Петров"}, nil); code != http.StatusBadRequest {
+		t.Errorf("Для пустых частей имени ожидался код %d, получен %d", http.StatusBadRequest, code)
+	}
+
+	var batch struct {
+		Results []struct {
+			Result *matcher.MatchResult `json:"result"`
+			Error  string               `json:"error"`
+		} `json:"results"`
+	}
+	postJSON(t, fmt.Sprintf("%s/api/match_names/batch", baseURL), map[string]interface{}{
+		"pairs": []map[string]interface{}{
+			{"name1_parts": map[string]string{"last": "Ivanov", "first": "Ivan"}, "name2": "Иванов Иван Петрович"},
+		},
+	}, &batch)
+	if len(batch.Results) != 1 || batch.Results[0].Result == nil || batch.Results[0].Result.MatchType == "no_match" {
+		t.Errorf("Пакетное сравнение имени по частям: %+v", batch.Results)
+	}
+}
+
+// TestSearch проверяет поиск лучших кандидатов для имени в списке
+func TestSearch(t *testing.T) {
+	setupTestServer(t)
+	defer teardownTestServer(t)
+
+	candidates := []string{
+		"Петров Петр",
+		"Ivanov Ivan Petrovich",
+		"Сидорова Анна",
+		"Иванов Иван Петрович",
+		"Smith John",
+	}
+
+	var response struct {
+		Matches []matcher.Candidate `json:"matches"`
+	}
+
+	apiURL := fmt.Sprintf("%s/api/search", baseURL)
+	code := postJSON(t, apiURL, map[string]interface{}{
+		"query":      "Иванов Иван Петрович",
+		"candidates": candidates,
+		"k":          2,
+	}, &response)
+	if code != http.StatusOK {
+		t.Fatalf("Неожиданный код ответа: %d", code)
+	}
+
+	if len(response.Matches) != 2 {
+		t.Fatalf("Ожидалось 2 кандидата, получено %d", len(response.Matches))
+	}
+
+	// Точное совпадение должно быть первым, транслитерация - вторым
+	if response.Matches[0].Index != 3 || response.Matches[0].Result.MatchType != "exact_match" {
+		t.Errorf("Первым ожидался точный кандидат #3, получен: %+v", response.Matches[0])
+	}
+	if response.Matches[1].Index != 1 || response.Matches[1].Name != candidates[1] {
+		t.Errorf("Вторым ожидался кандидат #1, получен: %+v", response.Matches[1])
+	}
+	if response.Matches[0].Result.Score < response.Matches[1].Result.Score {
+		t.Errorf("Кандидаты не упорядочены по убыванию оценки")
+	}
+
+	// Порог min_score отсекает слабых кандидатов
+	code = postJSON(t, apiURL, map[string]interface{}{
+		"query":      "Иванов Иван Петрович",
+		"candidates": candidates,
+		"min_score":  100,
+	}, &response)
+	if code != http.StatusOK {
+		t.Fatalf("Неожиданный код ответа: %d", code)
+	}
+	if len(response.Matches) != 1 {
+		t.Errorf("С min_score=100 ожидался 1 кандидат, получено %d", len(response.Matches))
+	}
+
+	// Тело больше MaxSearchBodySize не читается до конца
+	body := io.MultiReader(strings.NewReader(`{"query": "Иванов", "candidates": ["`), io.LimitReader(repeatReader('a'), api.MaxSearchBodySize))
+	recorder := httptest.NewRecorder()
+	api.SearchHandler(recorder, httptest.NewRequest(http.MethodPost, "/api/search", body))
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Для слишком большого тела ожидался код %d, получен %d", http.StatusRequestEntityTooLarge, recorder.Code)
+	}
+}
@@ -0,0 +1,274 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/x0rium/compareNames/matcher"
+	"github.com/x0rium/compareNames/matcher/utils"
+)
+
+// TestNicknameDictionary проверяет словарь имен: соответствия многие-ко-многим,
+// загрузку из JSON и CSV и перезагрузку при изменении файлов
+func TestNicknameDictionary(t *testing.T) {
+	dictionary := utils.NewNameDictionary()
+
+	related := [][2]string{{"иван", "ваня"}, {"ваня", "john"}, {"Иван", "Johann"}, {"алексей", "alex"}, {"александр", "алекс"}}
+	for _, pair := range related {
+		if !dictionary.Related(pair[0], pair[1]) {
+			t.Errorf("%s и %s должны быть формами одного имени", pair[0], pair[1])
+		}
+	}
+	if dictionary.Related("владислав", "вячеслав") {
+		t.Error("Владислав и Вячеслав - разные имена с общей формой")
+	}
+	if gender := dictionary.Gender("саша"); gender != utils.GenderUnknown {
+		t.Errorf("Саша - форма мужского и женского имени, получен пол %q", gender)
+	}
+
+	// Устаревшая карта Nicknames заполняется встроенными записями словаря
+	if nicknames := utils.Nicknames["александр"]; !slices.Contains(nicknames, "саша") {
+		t.Errorf("utils.Nicknames: ожидалась форма саша для имени александр, получено %v", nicknames)
+	}
+
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "names.json")
+	csvPath := filepath.Join(dir, "names.csv")
+	writeFile(t, jsonPath, `[{"name": "Зиновий", "gender": "male", "variants": ["Зяма", "Zinovy"]}]`)
+	writeFile(t, csvPath, "name,variant,gender\nфилипп,филя,male\n")
+
+	if err := dictionary.LoadFiles(jsonPath, csvPath); err != nil {
+		t.Fatalf("Ошибка загрузки словаря: %v", err)
+	}
+	if !dictionary.Related("зиновий", "zinovy") || !dictionary.Related("Филипп", "Филя") || !dictionary.IsGivenName("zinovij") {
+		t.Error("Записи из файлов JSON и CSV не загружены")
+	}
+	if !dictionary.Related("иван", "ваня") {
+		t.Error("Встроенные записи должны сохраняться при загрузке файлов")
+	}
+
+	// Изменение файла подхватывается при наблюдении
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloaded := make(chan error, 1)
+	go dictionary.Watch(ctx, 10*time.Millisecond, func(err error) { reloaded <- err }, csvPath)
+	time.Sleep(50 * time.Millisecond) // Даем наблюдателю запомнить исходное время изменения
+
+	writeFile(t, csvPath, "name,variant,gender\nфилипп,филипок,male\n")
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(csvPath, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Fatalf("Ошибка перезагрузки словаря: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Словарь не перезагружен после изменения файла")
+	}
+	if !dictionary.Related("филипп", "филипок") || dictionary.Related("филипп", "филя") {
+		t.Error("После перезагрузки ожидались записи из измененного файла")
+	}
+
+	if _, err := utils.ParseDictionary(strings.NewReader("филипп\n"), utils.DictionaryFormatCSV); err == nil {
+		t.Error("Для строки CSV без формы имени ожидалась ошибка")
+	}
+}
+
+// TestNicknameDictionaryAPI проверяет пополнение словаря имен через API во время работы
+func TestNicknameDictionaryAPI(t *testing.T) {
+	setupTestServer(t)
+	defer teardownTestServer(t)
+
+	matchURL := fmt.Sprintf("%s/api/match_names", baseURL)
+	dictionaryURL := fmt.Sprintf("%s/api/dictionary/nicknames", baseURL)
+
+	// Запись остается в общем словаре после первого запуска теста
+	var before matcher.MatchResult
+	postJSON(t, matchURL, RequestBody{Name1: "Ярополк", Name2: "Полкаша"}, &before)
+	if !utils.Dictionary().Related("Ярополк", "Полкаша") && hasBonus(before, matcher.BonusNameForm) {
+		t.Fatalf("Бонус %s до пополнения словаря не ожидался", matcher.BonusNameForm)
+	}
+
+	var added struct {
+		Entries []utils.DictionaryEntry `json:"entries"`
+	}
+	code := postJSON(t, dictionaryURL, map[string]interface{}{
+		"entries": []utils.DictionaryEntry{{Name: "Ярополк", Gender: "male", Variants: []string{"Полкаша"}}},
+	}, &added)
+	if code != http.StatusOK || len(added.Entries) != 1 || added.Entries[0].Name != "ярополк" {
+		t.Fatalf("Неожиданный ответ при добавлении записи: %d %+v", code, added)
+	}
+
+	// Результат из кэша сбрасывается после изменения словаря
+	var after matcher.MatchResult
+	postJSON(t, matchURL, RequestBody{Name1: "Ярополк", Name2: "Полкаша"}, &after)
+	if after.FromCache || !hasBonus(after, matcher.BonusNameForm) || after.Score < before.Score {
+		t.Errorf("После пополнения словаря ожидался бонус %s: %+v", matcher.BonusNameForm, after)
+	}
+
+	if code := postJSON(t, dictionaryURL, map[string]interface{}{
+		"entries": []utils.DictionaryEntry{{Name: "Ярополк", Gender: "unknown"}},
+	}, nil); code != http.StatusBadRequest {
+		t.Errorf("Для неизвестного пола ожидался код %d, получен %d", http.StatusBadRequest, code)
+	}
+}
+
+// TestNicknameSubstitution проверяет сравнение с заменой уменьшительной формы полной формой имени
+func TestNicknameSubstitution(t *testing.T) {
+	// Уменьшительная форма в полном имени дает возможное совпадение при любом кодировщике,
+	// метрики результата относятся к исходным именам
+	russian := matcher.DefaultConfig()
+	russian.PhoneticEncoder = matcher.PhoneticRussian
+	for _, config := range []matcher.Config{matcher.DefaultConfig(), russian} {
+		result := matcher.MatchNames("Саша Петров", "Александр Петров", nil, &config)
+		if result.MatchType != "possible_match" || substitutionWeight(result) != 0.8 {
+			t.Errorf("Саша Петров/Александр Петров (%s): ожидалось возможное совпадение по словарю, получено %d %s",
+				config.PhoneticEncoder, result.Score, result.MatchType)
+		} else if substitution := result.Explanation.Substitution; substitution.Kind != matcher.SubstitutionNickname ||
+			substitution.Part1 != "Саша" || substitution.Part2 != "Александр" {
+			t.Errorf("Саша Петров/Александр Петров: ожидалась замена Саша - Александр, получено %+v", substitution)
+		}
+	}
+
+	// Одно уменьшительное имя совпадением не считается
+	if single := matcher.MatchNames("Саша", "Александр", nil, nil); single.MatchType != "no_match" {
+		t.Errorf("Саша/Александр: ожидалось no_match, получено %d %s", single.Score, single.MatchType)
+	}
+
+	// Формы на разных алфавитах не заменяются: "Иван" и "John" - соответствие имен на разных языках,
+	// а не уменьшительная форма
+	if crossLanguage := matcher.MatchNames("Иван", "John", nil, nil); crossLanguage.MatchType != "no_match" || substitutionWeight(crossLanguage) != 0 {
+		t.Errorf("Иван/John: замена форм на разных алфавитах не ожидалась, получено %d %s", crossLanguage.Score, crossLanguage.MatchType)
+	}
+
+	cfg := matcher.DefaultConfig()
+	cfg.NicknameWeight = 0
+	if disabled := matcher.MatchNames("Саша Петров", "Александр Петров", nil, &cfg); substitutionWeight(disabled) != 0 {
+		t.Errorf("При NicknameWeight = 0 замена не ожидалась: %+v", disabled.Explanation)
+	}
+}
+
+// TestCognates проверяет межъязыковые соответствия имен: оценку соответствия слов,
+// сравнение имен, локализованных вместо транслитерации, и пополнение групп через API
+func TestCognates(t *testing.T) {
+	dictionary := utils.NewNameDictionary()
+
+	scores := []struct {
+		word1, word2 string
+		expected     float64
+	}{
+		{"Пётр", "Pierre", 1},
+		{"Peter", "Piotr", 1},
+		{"Иосиф", "José", 1},
+		{"Jozef", "Józef", 0}, // Написание без диакритики
+		{"Pyotr", "Pedro", 1}, // Транслитерация формы группы
+		{"Петя", "Pierre", 0.8},
+		{"Пётр", "Pyotr", 0}, // Транслитерация, а не соответствие
+		{"Петр", "Петя", 0},  // Уменьшительная форма
+		{"Иван", "Pierre", 0},
+	}
+	for _, s := range scores {
+		if got := dictionary.CognateScore(s.word1, s.word2); got != s.expected {
+			t.Errorf("CognateScore(%q, %q) = %v, ожидалось %v", s.word1, s.word2, got, s.expected)
+		}
+	}
+	if utils.GivenNameGender("Giuseppe") != utils.GenderMale || utils.GivenNameGender("Giulia") != utils.GenderFemale {
+		t.Error("Для форм групп соответствий ожидался пол группы")
+	}
+	if utils.IsGivenName("Ludwig") {
+		t.Error("Формы групп соответствий не должны влиять на разбор ролей частей имени")
+	}
+
+	// Долей CognateWeight оценивается только замененное имя: полное имя может совпасть,
+	// одно имя на другом языке дает возможное совпадение
+	result := matcher.MatchNames("Иосиф Бродский", "Joseph Brodsky", nil, nil)
+	if result.CognateScore != 1 || result.MatchType != "match" || substitutionWeight(result) != 0.95 {
+		t.Errorf("Имя на другом языке при совпадающей фамилии должно давать совпадение: %+v", result)
+	}
+	localized := matcher.MatchNames("Иосиф Бродский", "José Бродский", nil, nil)
+	if localized.ExactMatch || localized.MatchType != "match" || localized.LevenshteinScore == 1 || substitutionWeight(localized) != 0.95 {
+		t.Errorf("Имена, совпавшие после замены, должны оцениваться метриками исходных имен, а не точным совпадением: %+v", localized)
+	}
+	single := matcher.MatchNames("Иосиф", "José", nil, nil)
+	if single.ExactMatch || single.MatchType != "possible_match" || substitutionWeight(single) != 0.9 {
+		t.Errorf("Соответствие одного имени должно давать возможное совпадение: %+v", single)
+	}
+
+	// Результат описывает сравнение исходных имен, а замена - отдельной записью пояснения
+	pierre := matcher.MatchNames("Пётр", "Pierre", nil, nil)
+	if pierre.BestMatch2 != "Pierre" || pierre.LevenshteinScore == 1 || substitutionWeight(pierre) == 0 {
+		t.Errorf("Пётр/Pierre: ожидались метрики исходных имен и замена в пояснении: %+v", pierre)
+	} else if substitution := pierre.Explanation.Substitution; substitution.Kind != matcher.SubstitutionCognate ||
+		substitution.Part1 != "Пётр" || substitution.Part2 != "Pierre" {
+		t.Errorf("Пётр/Pierre: ожидалась замена Пётр - Pierre, получено %+v", substitution)
+	}
+	if len(pierre.Explanation.AlignedParts) != 1 || pierre.Explanation.AlignedParts[0].Part2 != "Pierre" {
+		t.Errorf("Пётр/Pierre: ожидались сопоставленные части исходных имен, получено %+v", pierre.Explanation.AlignedParts)
+	}
+
+	cfg := matcher.DefaultConfig()
+	cfg.CognateWeight = 0
+	unweighted := matcher.MatchNames("Иосиф Бродский", "Joseph Brodsky", nil, &cfg)
+	if unweighted.Score >= result.Score || !hasBonus(unweighted, matcher.BonusCognate) {
+		t.Errorf("Без замены имени ожидались только бонус %s и оценка ниже %d: %+v", matcher.BonusCognate, result.Score, unweighted)
+	}
+
+	if err := dictionary.AddCognates(utils.CognateCluster{Gender: "male", Forms: []string{"Ярополк"}}); err == nil {
+		t.Error("Для группы из одной формы ожидалась ошибка")
+	}
+
+	setupTestServer(t)
+	defer teardownTestServer(t)
+
+	var added struct {
+		Clusters []utils.CognateCluster `json:"clusters"`
+	}
+	code := postJSON(t, fmt.Sprintf("%s/api/dictionary/cognates", baseURL), map[string]interface{}{
+		"clusters": []utils.CognateCluster{{Gender: "male", Forms: []string{"Святослав", "Svatoslav", "Świętosław"}}},
+	}, &added)
+	if code != http.StatusOK || len(added.Clusters) != 1 || len(added.Clusters[0].Forms) != 3 {
+		t.Fatalf("Неожиданный ответ при добавлении группы: %d %+v", code, added)
+	}
+	if utils.Dictionary().CognateScore("Святослав", "Swietoslaw") != 1 {
+		t.Error("Добавленная через API группа соответствий не используется")
+	}
+}
+
+// hasBonus проверяет, сработал ли бонус в пояснении к оценке
+func hasBonus(result matcher.MatchResult, name string) bool {
+	if result.Explanation == nil {
+		return false
+	}
+	for _, bonus := range result.Explanation.Bonuses {
+		if bonus.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// substitutionWeight возвращает множитель оценки сравнения с заменой формы имени (0 - замены не было)
+func substitutionWeight(result matcher.MatchResult) float64 {
+	if result.Explanation == nil || result.Explanation.Substitution == nil {
+		return 0
+	}
+	return result.Explanation.Substitution.Weight
+}
+
+// writeFile записывает файл для теста
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,83 @@
+package e2e
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/x0rium/compareNames/matcher"
+)
+
+// TestNameIndex проверяет поиск по индексу имен, удаление и сохранение индекса
+func TestNameIndex(t *testing.T) {
+	index := matcher.NewNameIndex(nil)
+	index.Add("1", "Петров Сергей")
+	index.Add("2", "Иванов Иван Петрович")
+	index.Add("3", "Смирнова Ольга")
+	index.Add("4", "Ivanov Ivan Petrovich")
+
+	if index.Len() != 4 {
+		t.Fatalf("Ожидалось 4 имени в индексе, получено %d", index.Len())
+	}
+
+	matches := index.Query("Иванов Иван Петрович", 2)
+	if len(matches) != 2 {
+		t.Fatalf("Ожидалось 2 результата, получено %d", len(matches))
+	}
+	if matches[0].ID != "2" || matches[1].ID != "4" {
+		t.Errorf("Ожидались идентификаторы 2 и 4, получено %s и %s", matches[0].ID, matches[1].ID)
+	}
+
+	// Имена без общих ключей блокировки не сравниваются
+	for _, match := range index.Query("Иванов Иван Петрович", 0) {
+		if match.ID == "3" {
+			t.Errorf("Имя %q не должно попадать в кандидаты", match.Name)
+		}
+	}
+
+	if !index.Remove("2") || index.Remove("2") {
+		t.Errorf("Remove должен удалять имя ровно один раз")
+	}
+
+	var buf bytes.Buffer
+	if err := index.Save(&buf); err != nil {
+		t.Fatalf("Ошибка при сохранении индекса: %v", err)
+	}
+	loaded, err := matcher.LoadNameIndex(&buf, nil)
+	if err != nil {
+		t.Fatalf("Ошибка при загрузке индекса: %v", err)
+	}
+	if loaded.Len() != 3 {
+		t.Fatalf("Ожидалось 3 имени в загруженном индексе, получено %d", loaded.Len())
+	}
+
+	matches = loaded.Query("Иванов Иван Петрович", 1)
+	if len(matches) != 1 || matches[0].ID != "4" {
+		t.Errorf("Ожидался идентификатор 4, получено %+v", matches)
+	}
+}
+
+// TestNameIndexBlocking проверяет уровни ключей блокировки и части имени без букв после транслитерации
+func TestNameIndexBlocking(t *testing.T) {
+	index := matcher.NewNameIndex(nil)
+	index.Add("1", "Смирнова Ольга")
+	index.Add("2", "Соколов Семен")
+	index.Add("3", "Ъ")
+	index.Add("4", "Ь Иван")
+
+	// Имя с той же первой буквой, но без общих фонетических ключей и триграмм не сравнивается
+	for _, match := range index.Query("Смирнова Ольга", 0) {
+		if match.ID == "2" {
+			t.Errorf("Имя %q не должно попадать в кандидаты по первой букве", match.Name)
+		}
+	}
+
+	if matches := index.Query("Ь Иван", 1); len(matches) != 1 || matches[0].ID != "4" {
+		t.Errorf("Ожидался идентификатор 4, получено %+v", matches)
+	}
+	index.Query("Ъ", 0)
+
+	// Запрос из инициалов находит кандидатов по первым буквам
+	if matches := index.Query("С. О.", 0); len(matches) == 0 {
+		t.Error("Для инициалов ожидались кандидаты по первым буквам")
+	}
+}
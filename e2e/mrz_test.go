@@ -0,0 +1,125 @@
+package e2e
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/x0rium/compareNames/matcher"
+	"github.com/x0rium/compareNames/matcher/mrz"
+	"github.com/x0rium/compareNames/matcher/translit"
+)
+
+// td3Lines формирует строки MRZ паспорта с корректными контрольными цифрами
+func td3Lines(name, number, birthDate, sex, expiryDate string) []string {
+	line1 := "P<RUS" + name
+	line1 += strings.Repeat("<", 44-len(line1))
+
+	withDigit := func(value string) string {
+		return value + fmt.Sprint(mrz.CheckDigit(value))
+	}
+	line2 := withDigit(number) + "RUS" + withDigit(birthDate) + sex + withDigit(expiryDate) + strings.Repeat("<", 15)
+	composite := line2[0:10] + line2[13:20] + line2[21:43]
+
+	return []string{line1, line2 + fmt.Sprint(mrz.CheckDigit(composite))}
+}
+
+// TestMRZParse проверяет разбор MRZ форматов TD3 и TD1 на образцах ICAO Doc 9303
+func TestMRZParse(t *testing.T) {
+	td3, err := mrz.Parse([]string{
+		"P<UTOERIKSSON<<ANNA<MARIA<<<<<<<<<<<<<<<<<<<",
+		"L898902C36UTO7408122F1204159ZE184226B<<<<<10",
+	})
+	if err != nil {
+		t.Fatalf("Ошибка разбора TD3: %v", err)
+	}
+	if td3.Format != mrz.FormatTD3 || td3.Surname != "ERIKSSON" || td3.GivenNames != "ANNA MARIA" ||
+		td3.DocumentNumber != "L898902C3" || td3.Sex != "F" || td3.BirthDate.Format("2006-01-02") != "1974-08-12" {
+		t.Errorf("Неожиданный результат разбора TD3: %+v", td3)
+	}
+
+	td1, err := mrz.Parse([]string{
+		"I<UTOD231458907<<<<<<<<<<<<<<<",
+		"7408122F1204159UTO<<<<<<<<<<<6",
+		"ERIKSSON<<ANNA<MARIA<<<<<<<<<<",
+	})
+	if err != nil {
+		t.Fatalf("Ошибка разбора TD1: %v", err)
+	}
+	if td1.Format != mrz.FormatTD1 || td1.FullName() != "ERIKSSON ANNA MARIA" || td1.DocumentNumber != "D23145890" ||
+		td1.ExpiryDate.Format("2006-01-02") != "2012-04-15" {
+		t.Errorf("Неожиданный результат разбора TD1: %+v", td1)
+	}
+
+	// Искаженная контрольная цифра даты рождения
+	_, err = mrz.Parse([]string{
+		"P<UTOERIKSSON<<ANNA<MARIA<<<<<<<<<<<<<<<<<<<",
+		"L898902C36UTO7408123F1204159ZE184226B<<<<<10",
+	})
+	if !errors.Is(err, mrz.ErrCheckDigit) {
+		t.Errorf("Ожидалась ошибка контрольной цифры, получено %v", err)
+	}
+
+	if _, err = mrz.Parse([]string{"P<UTOERIKSSON"}); !errors.Is(err, mrz.ErrInvalidFormat) {
+		t.Errorf("Ожидалась ошибка формата, получено %v", err)
+	}
+}
+
+// TestMatchNameToMRZ проверяет сравнение имени с MRZ и передачу данных MRZ в атрибуты
+func TestMatchNameToMRZ(t *testing.T) {
+	lines := td3Lines("SHCHERBAKOVA<<KSENIIA", "751234567", "850315", "F", "300101")
+
+	result, err := matcher.MatchNameToMRZ("Щербакова Ксения", lines, nil, nil)
+	if err != nil {
+		t.Fatalf("Ошибка сравнения с MRZ: %v", err)
+	}
+	if result.Score < 90 {
+		t.Errorf("Ожидалось совпадение, получено %d (%s)", result.Score, result.MatchType)
+	}
+
+	attrs := matcher.CreateAttributes()
+	attrs["birth_date"] = matcher.Attribute{Value1: "15.03.1985"}
+	attrs["gender"] = matcher.Attribute{Value1: "F"}
+	same, _ := matcher.MatchNameToMRZ("Щербакова Ксения Олеговна", lines, attrs, nil)
+
+	attrs["birth_date"] = matcher.Attribute{Value1: "16.03.1986"}
+	different, _ := matcher.MatchNameToMRZ("Щербакова Ксения Олеговна", lines, attrs, nil)
+
+	if same.AdditionalAttributesScore != 1 || different.AdditionalAttributesScore >= 1 || same.Score <= different.Score {
+		t.Errorf("Данные MRZ должны учитываться в атрибутах: %d (%.2f) против %d (%.2f)",
+			same.Score, same.AdditionalAttributesScore, different.Score, different.AdditionalAttributesScore)
+	}
+	// При точном совпадении имени оценка атрибутов сообщается, но оценка остается 100
+	exact, _ := matcher.MatchNameToMRZ("Щербакова Ксения", lines, attrs, nil)
+	if exact.Score != 100 || exact.AdditionalAttributesScore >= 1 {
+		t.Errorf("Ожидалось точное совпадение с оценкой атрибутов ниже 1: %d (%.2f)", exact.Score, exact.AdditionalAttributesScore)
+	}
+	if attrs["birth_date"].Value2 != "" {
+		t.Errorf("Исходные атрибуты не должны изменяться")
+	}
+}
+
+// TestMatchNameToMRZTruncated проверяет сравнение длинного имени с усеченным полем имени MRZ
+func TestMatchNameToMRZTruncated(t *testing.T) {
+	fullName := "Константинопольская Александра Владиславовна"
+	field := translit.MRZNameField("Константинопольская", "Александра Владиславовна", translit.MRZNameLength)
+	lines := td3Lines(field, "751234567", "850315", "F", "300101")
+
+	result, err := matcher.MatchNameToMRZ(fullName, lines, nil, nil)
+	if err != nil {
+		t.Fatalf("Ошибка сравнения с MRZ: %v", err)
+	}
+	if !result.ExactMatch {
+		t.Errorf("%s / %s: ожидалось точное совпадение с усеченным полем, получено %d (%s)",
+			fullName, field, result.Score, result.MatchType)
+	}
+
+	// Другое отчество в усеченной части поля не отличить от исходного
+	if other, _ := matcher.MatchNameToMRZ("Константинопольская Александра Владиславна", lines, nil, nil); !other.ExactMatch {
+		t.Errorf("Ожидалось точное совпадение по видимой части поля, получено %d (%s)", other.Score, other.MatchType)
+	}
+	if different, _ := matcher.MatchNameToMRZ("Константинопольская Анастасия Владиславовна", lines, nil, nil); different.ExactMatch {
+		t.Errorf("Другое имя не должно давать точного совпадения: %d (%s)", different.Score, different.MatchType)
+	}
+}
@@ -0,0 +1,150 @@
+package e2e
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/x0rium/compareNames/matcher"
+	"github.com/x0rium/compareNames/matcher/utils"
+)
+
+// TestParseName проверяет разбор имени на фамилию, имя, отчество и другие роли частей
+func TestParseName(t *testing.T) {
+	cases := []struct {
+		name      string
+		split     []string
+		roles     []string
+		confident bool
+	}{
+		{"Иванов Иван Петрович", []string{"Иванов", "Иван", "Петрович"}, []string{"surname", "given", "patronymic"}, true},
+		{"Петрович Иван Иванов", []string{"Иванов", "Иван", "Петрович"}, []string{"patronymic", "given", "surname"}, true},
+		{"Иван Петров", []string{"Петров", "Иван", ""}, []string{"given", "surname"}, true},
+		{"Абрамович Роман Аркадьевич", []string{"Абрамович", "Роман", "Аркадьевич"}, []string{"surname", "given", "patronymic"}, true},
+		{"Рабинович Михаил", []string{"Рабинович", "Михаил", ""}, []string{"surname", "given"}, true},
+		{"Петрова-Сидорова Анна Ивановна", []string{"Петрова Сидорова", "Анна", "Ивановна"}, []string{"surname", "surname", "given", "patronymic"}, true},
+		{"Мамедов Эльдар Рашид оглы", []string{"Мамедов", "Эльдар", "Рашид оглы"}, []string{"surname", "given", "patronymic", "patronymic"}, true},
+		{"Иванов И.П.", []string{"Иванов", "", ""}, []string{"surname", "initial", "initial"}, true},
+		{"John Smith", []string{"Smith", "John", ""}, []string{"given", "surname"}, true},
+		{"Ludwig van Beethoven Jr.", []string{"Beethoven", "Ludwig", ""}, []string{"given", "particle", "surname", "suffix"}, false},
+		{"Иванов Иван Иванович Сидоров", []string{"Иванов Сидоров", "Иван", "Иванович"}, []string{"surname", "given", "patronymic", "surname"}, true},
+		// Одни инициалы разбиваются по позиции, как до разбора ролей
+		{"A B C D E", []string{"A", "B", "C"}, []string{"initial", "initial", "initial", "initial", "initial"}, true},
+		{"X Y", []string{"X", "Y", ""}, []string{"initial", "initial"}, true},
+	}
+
+	for _, c := range cases {
+		parsed := utils.ParseName(c.name)
+		roles := make([]string, len(parsed.Parts))
+		for i, part := range parsed.Parts {
+			roles[i] = part.Role
+		}
+		if !reflect.DeepEqual(roles, c.roles) || parsed.Confident != c.confident {
+			t.Errorf("ParseName(%q): роли %v (уверенно: %v), ожидалось %v (%v)", c.name, roles, parsed.Confident, c.roles, c.confident)
+		}
+		if split := utils.SplitName(c.name); !reflect.DeepEqual(split, c.split) {
+			t.Errorf("SplitName(%q) = %q, ожидалось %q", c.name, split, c.split)
+		}
+	}
+}
+
+// TestMatchNamesRoles проверяет, что части имен сравниваются по ролям, а не по позиции
+func TestMatchNamesRoles(t *testing.T) {
+	swapped := matcher.MatchNames("Иван Петров", "Петр Иванов", nil, nil)
+	if swapped.MatchType != "no_match" {
+		t.Errorf("Иван Петров / Петр Иванов: ожидалось no_match, получено %s (%d)", swapped.MatchType, swapped.Score)
+	}
+
+	reordered := matcher.MatchNames("Иван Петров", "Петров Иван", nil, nil)
+	if reordered.MatchType != "match" {
+		t.Errorf("Иван Петров / Петров Иван: ожидалось match, получено %s (%d)", reordered.MatchType, reordered.Score)
+	}
+
+	parts := reordered.Explanation.AlignedParts
+	if len(parts) != 2 || parts[0].Role != "given" || parts[0].Part2 != "Иван" || parts[1].Role != "surname" || parts[1].Part2 != "Петров" {
+		t.Errorf("Неожиданное сопоставление частей: %+v", parts)
+	}
+}
+
+// TestMorphology проверяет приведение частей имени к именительному падежу и фамилии семьи
+func TestMorphology(t *testing.T) {
+	lemmas := []struct {
+		lemma  utils.Lemma
+		text   string
+		family string
+		gender string
+	}{
+		{utils.LemmatizeSurname("Иванову"), "иванов", "иванов", utils.GenderMale},
+		{utils.LemmatizeSurname("Ивановой"), "иванова", "иванов", utils.GenderFemale},
+		{utils.LemmatizeSurname("Достоевского"), "достоевский", "достоевский", utils.GenderMale},
+		{utils.LemmatizeSurname("Достоевская"), "достоевская", "достоевский", utils.GenderFemale},
+		{utils.LemmatizeSurname("Dostoevskaya"), "dostoevskaya", "dostoevsky", utils.GenderFemale},
+		{utils.LemmatizeGivenName("Сергея"), "сергей", "сергей", utils.GenderMale},
+		{utils.LemmatizeGivenName("Анны"), "анна", "анна", utils.GenderFemale},
+		{utils.LemmatizePatronymic("Петровной"), "петровна", "петровна", utils.GenderFemale},
+	}
+	for _, c := range lemmas {
+		if c.lemma.Text != c.text || c.lemma.Family != c.family || c.lemma.Gender != c.gender {
+			t.Errorf("Лемма %+v, ожидалось %s (%s, %s)", c.lemma, c.text, c.family, c.gender)
+		}
+	}
+
+	normalized := map[string]string{
+		"Иванову Ивану Петровичу": "Иванов Иван Петрович",
+		"Петрова Ивана":           "Петров Иван",
+		"Петрова Анна":            "Петрова Анна",
+		"Иванову Анну":            "Иванова Анна",
+	}
+	for name, expected := range normalized {
+		if got := utils.NormalizeMorphology(utils.ParseName(name)).Text(); got != expected {
+			t.Errorf("NormalizeMorphology(%q) = %q, ожидалось %q", name, got, expected)
+		}
+	}
+
+	if result := matcher.MatchNames("Достоевского Федора Михайловича", "Достоевский Федор Михайлович", nil, nil); result.MatchType != "match" {
+		t.Errorf("Косвенный падеж: ожидалось match, получено %s (%d)", result.MatchType, result.Score)
+	}
+
+	cfg := matcher.DefaultConfig()
+	cfg.GenderPairedSurnamesAsFamily = true
+	family := matcher.MatchNames("Ivanov Alex", "Ivanova Alex", nil, &cfg)
+	if !family.SameFamily || family.MatchType != "possible_match" {
+		t.Errorf("Ivanov / Ivanova: ожидалась одна семья и possible_match, получено %s (%d), same_family=%v",
+			family.MatchType, family.Score, family.SameFamily)
+	}
+}
+
+// TestGenderConflict проверяет определение пола по имени и снижение оценки при разном поле
+func TestGenderConflict(t *testing.T) {
+	genders := map[string]string{
+		"Александр Иванов":          utils.GenderMale,
+		"Александра Иванова":        utils.GenderFemale,
+		"Петрова Ивана":             utils.GenderMale,
+		"Мамедов Эльдар Рашид оглы": utils.GenderMale,
+		"Elizabeth Smith":           utils.GenderFemale,
+		"Женя":                      utils.GenderUnknown,
+	}
+	for name, expected := range genders {
+		if got := utils.InferGender(utils.ParseName(name)); got != expected {
+			t.Errorf("InferGender(%q) = %q, ожидалось %q", name, got, expected)
+		}
+	}
+
+	result := matcher.MatchNames("Александр Иванов", "Александра Иванова", nil, nil)
+	if !result.GenderConflict || result.Explanation == nil || result.Explanation.GenderPenalty == 0 {
+		t.Fatalf("Ожидалось снижение оценки за разный пол: %+v", result)
+	}
+	// Бонусы и ограничение 0.99 не поглощают снижение: имена разного пола - не совпадение
+	for _, pair := range [][2]string{{"Александр Иванов", "Александра Иванова"}, {"Достоевский", "Достоевская"}} {
+		if conflict := matcher.MatchNames(pair[0], pair[1], nil, nil); !conflict.GenderConflict || conflict.MatchType == "match" {
+			t.Errorf("%s / %s: ожидался разный пол без совпадения, получено %d %s, gender_conflict=%v",
+				pair[0], pair[1], conflict.Score, conflict.MatchType, conflict.GenderConflict)
+		}
+	}
+
+	cfg := matcher.DefaultConfig()
+	cfg.GenderConflictPenalty = 0
+	unpenalized := matcher.MatchNames("Александр Иванов", "Александра Иванова", nil, &cfg)
+	if !unpenalized.GenderConflict || unpenalized.Score <= result.Score {
+		t.Errorf("Без штрафа ожидалась оценка выше %d, получено %d", result.Score, unpenalized.Score)
+	}
+}
@@ -0,0 +1,86 @@
+package e2e
+
+import (
+	"testing"
+
+	"github.com/x0rium/compareNames/matcher"
+)
+
+// TestPipelinesComparison прогоняет тестовые случаи через оба алгоритма сравнения
+// и выводит расхождения. Типы совпадения из cases.json проверяются для алгоритма
+// по умолчанию, для стратегий пакета compare - точные совпадения
+func TestPipelinesComparison(t *testing.T) {
+	testCases := LoadTestCases(t)
+
+	legacyConfig := matcher.DefaultConfig()
+	strategyConfig := matcher.DefaultConfig()
+	strategyConfig.Pipeline = matcher.PipelineStrategy
+
+	validTypes := map[string]bool{
+		"exact_match":    true,
+		"match":          true,
+		"possible_match": true,
+		"no_match":       true,
+	}
+
+	legacyAgreed, strategyAgreed := 0, 0
+	for _, tc := range testCases {
+		legacy := matcher.MatchNames(tc.Name1, tc.Name2, nil, &legacyConfig)
+		strategy := matcher.MatchNames(tc.Name1, tc.Name2, nil, &strategyConfig)
+
+		if !validTypes[strategy.MatchType] {
+			t.Errorf("Недопустимый тип совпадения %q для %s <-> %s", strategy.MatchType, tc.Name1, tc.Name2)
+		}
+		if strategy.Score < 0 || strategy.Score > 100 {
+			t.Errorf("Оценка вне диапазона 0-100: %d для %s <-> %s", strategy.Score, tc.Name1, tc.Name2)
+		}
+
+		for pipeline, result := range map[string]matcher.MatchResult{
+			matcher.PipelineLegacyBonus: legacy,
+			matcher.PipelineStrategy:    strategy,
+		} {
+			if result.ExactMatch != (result.MatchType == "exact_match") {
+				t.Errorf("%s: %s <-> %s: признак точного совпадения %v не соответствует типу %q",
+					pipeline, tc.Name1, tc.Name2, result.ExactMatch, result.MatchType)
+			}
+		}
+		if tc.ExpectedExactMatch != strategy.ExactMatch {
+			t.Errorf("%s: %s <-> %s: стратегии вернули точное совпадение %v, ожидалось %v",
+				tc.Name, tc.Name1, tc.Name2, strategy.ExactMatch, tc.ExpectedExactMatch)
+		}
+
+		if legacy.MatchType == tc.ExpectedMatchType {
+			legacyAgreed++
+		} else {
+			t.Errorf("%s: %s <-> %s: ожидался тип %q, получен %q",
+				tc.Name, tc.Name1, tc.Name2, tc.ExpectedMatchType, legacy.MatchType)
+		}
+		if strategy.MatchType == tc.ExpectedMatchType {
+			strategyAgreed++
+		}
+
+		if legacy.MatchType != strategy.MatchType {
+			t.Logf("⚖️ %s: %s <-> %s: %s=%d/%s, %s=%d/%s (ожидается %s)",
+				tc.Name, tc.Name1, tc.Name2,
+				matcher.PipelineLegacyBonus, legacy.Score, legacy.MatchType,
+				matcher.PipelineStrategy, strategy.Score, strategy.MatchType,
+				tc.ExpectedMatchType)
+		}
+	}
+
+	// Точное совпадение одинаково в обоих алгоритмах: имена равны после предобработки
+	for _, config := range []matcher.Config{legacyConfig, strategyConfig} {
+		for _, pair := range [][2]string{{"Иван  Иванов", "иван иванов"}, {"Петрова-Сидорова Анна", "Петрова Сидорова Анна"}, {"O'Brien John", "OBrien John"}} {
+			if result := matcher.MatchNames(pair[0], pair[1], nil, &config); result.MatchType != "exact_match" || !result.ExactMatch {
+				t.Errorf("%s: %s <-> %s: ожидалось exact_match, получено %d %s", config.Pipeline, pair[0], pair[1], result.Score, result.MatchType)
+			}
+		}
+		if result := matcher.MatchNames("J Smith", "John Smith", nil, &config); result.ExactMatch {
+			t.Errorf("%s: J Smith <-> John Smith: инициалы не должны считаться точным совпадением", config.Pipeline)
+		}
+	}
+
+	t.Logf("📊 Совпадение с ожиданиями: %s=%d/%d, %s=%d/%d",
+		matcher.PipelineLegacyBonus, legacyAgreed, len(testCases),
+		matcher.PipelineStrategy, strategyAgreed, len(testCases))
+}
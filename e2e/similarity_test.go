@@ -0,0 +1,154 @@
+package e2e
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/x0rium/compareNames/matcher"
+	"github.com/x0rium/compareNames/matcher/similarity"
+)
+
+// TestSimilarityUnicode проверяет, что метрики считают символы, а не байты,
+// и кириллические пары оцениваются так же, как аналогичные латинские
+func TestSimilarityUnicode(t *testing.T) {
+	pairs := [][2][2]string{
+		{{"Иванов", "Ивонов"}, {"Ivanov", "Ivonov"}},
+		{{"Михаил", "Михаал"}, {"Mikhail", "Mikhaal"}},
+		{{"Сергей", "Серей"}, {"Sergey", "Serey"}},
+	}
+	for _, pair := range pairs {
+		cyrillic, latin := pair[0], pair[1]
+		if d := similarity.LevenshteinDistance(cyrillic[0], cyrillic[1]); d != 1 {
+			t.Errorf("LevenshteinDistance(%q, %q): ожидалось 1, получено %d", cyrillic[0], cyrillic[1], d)
+		}
+		if got, want := similarity.LevenshteinSimilarity(cyrillic[0], cyrillic[1]), similarity.LevenshteinSimilarity(latin[0], latin[1]); got != want {
+			t.Errorf("LevenshteinSimilarity %v = %.4f, %v = %.4f", cyrillic, got, latin, want)
+		}
+	}
+
+	if got, want := similarity.JaroWinklerSimilarity("Иванов", "Ивонов"), similarity.JaroWinklerSimilarity("Ivanov", "Ivonov"); got != want {
+		t.Errorf("JaroWinklerSimilarity: кириллица %.4f, латиница %.4f", got, want)
+	}
+	// Слова без вторичного кода не совпадают только из-за этого; инициал совпадает с началом кода
+	if got := similarity.DoubleMetaphoneSimilarity("Ivan", "Igor"); got != 0 {
+		t.Errorf("DoubleMetaphoneSimilarity(Ivan, Igor): ожидалось 0, получено %.2f", got)
+	}
+	if got := similarity.DoubleMetaphoneSimilarity("J Smith", "John Smith"); got != 1 {
+		t.Errorf("DoubleMetaphoneSimilarity(J Smith, John Smith): ожидалось 1, получено %.2f", got)
+	}
+	if got := similarity.CosineSimilarity("Иванов", "иванов", 3); got != 1 {
+		t.Errorf("CosineSimilarity: ожидалось 1, получено %.4f", got)
+	}
+
+	// Оценка Левенштейна в результате сравнения нормируется на длину в символах
+	cyrillic := matcher.MatchNames("Иванов", "Ивонов", nil, nil)
+	latin := matcher.MatchNames("Ivanov", "Ivonov", nil, nil)
+	if cyrillic.LevenshteinScore != latin.LevenshteinScore || cyrillic.JaroWinklerScore != latin.JaroWinklerScore {
+		t.Errorf("Метрики различаются: кириллица lev=%.2f jw=%.2f, латиница lev=%.2f jw=%.2f",
+			cyrillic.LevenshteinScore, cyrillic.JaroWinklerScore, latin.LevenshteinScore, latin.JaroWinklerScore)
+	}
+	// Кириллица кодируется Double Metaphone по транслитерации и оценивается так же, как латиница.
+	// У "Sergey/Serey" различаются коды Soundex, поэтому пара не сравнивается
+	for _, pair := range pairs[:2] {
+		cyrillic := matcher.MatchNames(pair[0][0], pair[0][1], nil, nil)
+		latin := matcher.MatchNames(pair[1][0], pair[1][1], nil, nil)
+		if cyrillic.DoubleMetaphoneScore != latin.DoubleMetaphoneScore || cyrillic.Score != latin.Score || cyrillic.MatchType != latin.MatchType {
+			t.Errorf("Оценки различаются: %v %d (%s), %v %d (%s)",
+				pair[0], cyrillic.Score, cyrillic.MatchType, pair[1], latin.Score, latin.MatchType)
+		}
+	}
+}
+
+// TestTokenize проверяет общий токенизатор пакета similarity
+func TestTokenize(t *testing.T) {
+	cases := map[string][]string{
+		"Иванов  Иван\tПетрович": {"иванов", "иван", "петрович"},
+		"Петрова-Сидорова Анна":  {"петрова", "сидорова", "анна"},
+		"O'Neil, John": {"oneil", "john"},
+		"Иванов И.П.":  {"иванов", "ип"},
+		"Émile Zola":   {"émile", "zola"},
+		"  ":           nil,
+	}
+	for name, expected := range cases {
+		if got := similarity.Tokenize(name); !reflect.DeepEqual(got, expected) {
+			t.Errorf("Tokenize(%q): ожидалось %q, получено %q", name, expected, got)
+		}
+	}
+}
+
+// TestRussianPhonetic проверяет русский фонетический кодировщик и его выбор в конфигурации
+func TestRussianPhonetic(t *testing.T) {
+	groups := [][]string{
+		{"Шевчук", "Шевчюк", "Shevchuk"},
+		{"Ковалёв", "Ковалев", "Kovalev"},
+		{"Мария", "Марья"},
+		{"Смеётся", "Смеёца"},
+	}
+	for _, group := range groups {
+		code := similarity.RussianPhonetic(group[0])
+		for _, word := range group[1:] {
+			if got := similarity.RussianPhonetic(word); got != code {
+				t.Errorf("RussianPhonetic(%q) = %q, RussianPhonetic(%q) = %q", group[0], code, word, got)
+			}
+		}
+	}
+	if similarity.RussianPhoneticMatch("Иванов", "Петров") {
+		t.Error("Иванов и Петров не должны совпадать фонетически")
+	}
+
+	// Первая буква берется целиком, а не первым байтом UTF-8
+	if got := similarity.Soundex("Émile"); got != "É540" {
+		t.Errorf("Soundex(Émile): ожидалось É540, получено %q", got)
+	}
+
+	cfg := matcher.DefaultConfig()
+	cfg.PhoneticEncoder = matcher.PhoneticRussian
+	result := matcher.MatchNames("Шевчук", "Шевчюк", nil, &cfg)
+	if result.PhoneticScore != 1 {
+		t.Errorf("Шевчук/Шевчюк: ожидалась фонетическая оценка 1, получено %.2f", result.PhoneticScore)
+	}
+}
+
+// TestPhoneticEncoders проверяет фонетические кодировщики реестра и их набор с весами в конфигурации
+func TestPhoneticEncoders(t *testing.T) {
+	codes := map[string]map[string]string{
+		similarity.EncoderNYSIIS:     {"Knight": "NAGT", "Mackenzie": "MCANSY"},
+		similarity.EncoderCaverphone: {"Thompson": "TMPSN11111", "Lee": "LA11111111"},
+	}
+	for name, words := range codes {
+		encoder, ok := similarity.LookupEncoder(name)
+		if !ok {
+			t.Fatalf("Кодировщик %s не зарегистрирован", name)
+		}
+		for word, expected := range words {
+			if got := encoder.Encode(word); !reflect.DeepEqual(got, []string{expected}) {
+				t.Errorf("%s(%q): ожидалось %q, получено %q", name, word, expected, got)
+			}
+		}
+	}
+
+	if got := similarity.DaitchMokotoff("Peters"); !reflect.DeepEqual(got, []string{"734000", "739400"}) {
+		t.Errorf("DaitchMokotoff(Peters): получено %q", got)
+	}
+
+	// Еврейские и восточноевропейские фамилии в разных написаниях
+	for _, name := range []string{similarity.EncoderDaitchMokotoff, similarity.EncoderBeiderMorse} {
+		encoder, _ := similarity.LookupEncoder(name)
+		for _, pair := range [][2]string{{"Schwarzman", "Shvartsman"}, {"Шварцман", "Schwarzman"}, {"Moskowitz", "Moskovitz"}} {
+			if score := similarity.EncoderSimilarity(encoder, pair[0], pair[1]); score != 1 {
+				t.Errorf("%s: %s/%s ожидалась оценка 1, получено %.2f", name, pair[0], pair[1], score)
+			}
+		}
+	}
+
+	cfg := matcher.DefaultConfig()
+	cfg.PhoneticEncoders = map[string]float64{
+		matcher.PhoneticSoundex:        1,
+		matcher.PhoneticDaitchMokotoff: 1,
+		"unknown":                      1,
+	}
+	result := matcher.MatchNames("Schwarzman", "Shvartsman", nil, &cfg)
+	if result.PhoneticScore != 0.5 {
+		t.Errorf("Schwarzman/Shvartsman: ожидалась фонетическая оценка 0.5, получено %.2f", result.PhoneticScore)
+	}
+}
@@ -0,0 +1,289 @@
+package e2e
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/x0rium/compareNames/matcher"
+	"github.com/x0rium/compareNames/matcher/translit"
+)
+
+// TestTranslitTableStandard проверяет загрузку стандарта транслитерации из таблицы
+// и его использование при сравнении имен
+func TestTranslitTableStandard(t *testing.T) {
+	table := `{
+		"name": "bank_test",
+		"mapping": {"щ": "sch", "у": "u", "к": "k", "и": "i", "н": "n", "й": "y"},
+		"alternatives": {"щ": ["shch"]}
+	}`
+
+	std, err := translit.LoadTable(strings.NewReader(table))
+	if err != nil {
+		t.Fatalf("Ошибка загрузки таблицы: %v", err)
+	}
+	translit.Register(std.Name, std)
+
+	registered := false
+	for _, name := range translit.Standards() {
+		if name == "bank_test" {
+			registered = true
+		}
+	}
+	if !registered {
+		t.Fatalf("Стандарт bank_test не зарегистрирован")
+	}
+
+	if got := std.Transliterate("Щукин"); got != "schukin" {
+		t.Errorf("Transliterate: ожидалось schukin, получено %q", got)
+	}
+	if got := std.Reverse("Schukin"); got != "щукин" {
+		t.Errorf("Reverse: ожидалось щукин, получено %q", got)
+	}
+	if got := std.Variations("Щукин"); len(got) != 2 || got[1] != "shchukin" {
+		t.Errorf("Variations: неожиданный результат %v", got)
+	}
+
+	config := matcher.DefaultConfig()
+	config.TransliterationStandards = []string{"bank_test"}
+	result := matcher.MatchNames("Щукин", "Schukin", nil, &config)
+
+	if len(result.BestMatch1Standards) != 1 || result.BestMatch1Standards[0] != "bank_test" {
+		t.Errorf("Ожидался вариант по стандарту bank_test, получено %q %v", result.BestMatch1, result.BestMatch1Standards)
+	}
+}
+
+// TestTranslitICAO проверяет транслитерацию ICAO Doc 9303 и работу с полем имени MRZ
+func TestTranslitICAO(t *testing.T) {
+	cases := map[string]string{
+		"Юрий":      "iurii",
+		"Ксения":    "kseniia",
+		"Щербаков":  "shcherbakov",
+		"Объедков":  "obieedkov",
+		"Фёдор":     "fedor",
+		"Царёва":    "tsareva",
+		"Ильинична": "ilinichna",
+	}
+	for cyrillic, expected := range cases {
+		if got := translit.TranslitICAO(cyrillic); got != expected {
+			t.Errorf("TranslitICAO(%q): ожидалось %q, получено %q", cyrillic, expected, got)
+		}
+	}
+
+	if got := translit.TranslitICAOReverse("IURII"); got != "юрий" {
+		t.Errorf("TranslitICAOReverse: ожидалось юрий, получено %q", got)
+	}
+
+	field := translit.MRZNameField("Иванов", "Иван Петрович", translit.MRZNameLength)
+	if field != "IVANOV<<IVAN<PETROVICH<<<<<<<<<<<<<<<<<" {
+		t.Errorf("MRZNameField: неожиданное поле %q", field)
+	}
+	surname, given, truncated := translit.ParseMRZNameField(field)
+	if surname != "IVANOV" || given != "IVAN PETROVICH" || truncated {
+		t.Errorf("ParseMRZNameField: получено %q, %q, %v", surname, given, truncated)
+	}
+
+	long := translit.MRZNameField("Константинопольский", "Александр Александрович", translit.MRZNameLength)
+	if len(long) != translit.MRZNameLength {
+		t.Errorf("Поле MRZ должно иметь длину %d, получено %d", translit.MRZNameLength, len(long))
+	}
+	if _, _, truncated := translit.ParseMRZNameField(long); !truncated {
+		t.Errorf("Ожидался признак усечения для %q", long)
+	}
+
+	result := matcher.MatchNames("Ксения", "KSENIIA", nil, nil)
+	hasICAO := false
+	for _, standard := range result.BestMatch1Standards {
+		if standard == "icao" {
+			hasICAO = true
+		}
+	}
+	if !hasICAO || result.MatchType != "match" {
+		t.Errorf("Ожидалось совпадение через icao, получено %d %s %v", result.Score, result.MatchType, result.BestMatch1Standards)
+	}
+
+	// Написания из загранпаспорта совпадают с кириллическим именем и по фонетическим оценкам
+	passports := [][2]string{
+		{"Юрий", "IURII"},
+		{"Юрий", "Iurii"},
+		{"Наталья", "NATALIA"},
+		{"Андрей Юрьевич Щукин", "ANDREI IUREVICH SHCHUKIN"},
+	}
+	for _, pair := range passports {
+		result := matcher.MatchNames(pair[0], pair[1], nil, nil)
+		if result.MatchType != "match" || result.PhoneticScore != 1 || result.DoubleMetaphoneScore != 1 {
+			t.Errorf("%s <-> %s: ожидалось совпадение, получено %d %s (фонетика %.2f, DoubleMetaphone %.2f)",
+				pair[0], pair[1], result.Score, result.MatchType, result.PhoneticScore, result.DoubleMetaphoneScore)
+		}
+	}
+}
+
+// TestTranslitUkrainian проверяет украинскую транслитерацию на примерах из постановления КМУ № 55
+func TestTranslitUkrainian(t *testing.T) {
+	cases := map[string]string{
+		"Згорани":     "zghorany",
+		"Розгон":      "rozghon",
+		"Єнакієве":    "yenakiieve",
+		"Гаєвич":      "haievych",
+		"Їжакевич":    "yizhakevych",
+		"Кадиївка":    "kadyivka",
+		"Йосипівка":   "yosypivka",
+		"Стрий":       "stryi",
+		"Юрій":        "yurii",
+		"Корюківка":   "koriukivka",
+		"Яготин":      "yahotyn",
+		"Костянтин":   "kostiantyn",
+		"Знам'янка":   "znamianka",
+		"Феодосія":    "feodosiia",
+		"Ґалаґан":     "galagan",
+		"Олексій":     "oleksii",
+		"Біла Церква": "bila tserkva",
+	}
+	for cyrillic, expected := range cases {
+		if got := translit.TranslitUkrainian(cyrillic); got != expected {
+			t.Errorf("TranslitUkrainian(%q): ожидалось %q, получено %q", cyrillic, expected, got)
+		}
+	}
+
+	reverse := map[string]string{
+		"Yenakiieve":  "єнакієве",
+		"Yizhakevych": "їжакевич",
+		"Oleksii":     "олексій",
+		"Zghorany":    "згорани",
+		"Stryi":       "стрий",
+	}
+	for latin, expected := range reverse {
+		if got := translit.TranslitUkrainianReverse(latin); got != expected {
+			t.Errorf("TranslitUkrainianReverse(%q): ожидалось %q, получено %q", latin, expected, got)
+		}
+	}
+
+	if !translit.IsUkrainian("Олексій") || translit.IsUkrainian("Алексей") || translit.IsUkrainian("Oleksii") {
+		t.Errorf("IsUkrainian: неверное определение украинского текста")
+	}
+
+	result := matcher.MatchNames("Олексій Коваленко", "Oleksii Kovalenko", nil, nil)
+	if result.MatchType != "match" {
+		t.Errorf("Ожидалось совпадение, получено %d (%s)", result.Score, result.MatchType)
+	}
+}
+
+// TestTranslitLanguages проверяет определение языка и транслитерацию белорусских,
+// казахских, сербских и болгарских имен
+func TestTranslitLanguages(t *testing.T) {
+	languages := map[string]string{
+		"Жақсылықов Әлібек":  translit.LanguageKazakh,
+		"Новак Ђоковић":      translit.LanguageSerbian,
+		"Васілеўскі":         translit.LanguageBelarusian,
+		"Сцяпан Бірыла":      translit.LanguageBelarusian,
+		"Олексій":            translit.LanguageUkrainian,
+		"Ъглен":              translit.LanguageBulgarian,
+		"Съёмкин":            translit.LanguageRussian,
+		"Иван Петров":        "",
+		"Zhaksylykov Alibek": "",
+	}
+	for name, expected := range languages {
+		if got := translit.DetectLanguage(name); got != expected {
+			t.Errorf("DetectLanguage(%q): ожидалось %q, получено %q", name, expected, got)
+		}
+	}
+
+	cases := []struct {
+		fn       func(string) string
+		cyrillic string
+		expected string
+	}{
+		{translit.TranslitBelarusian, "Аляксандр Лукашэнка", "aliaksandr lukashenka"},
+		{translit.TranslitBelarusian, "Васілеўскі", "vasileuski"},
+		{translit.TranslitBelarusian, "Ефрасіння", "yefrasinnia"},
+		{translit.TranslitKazakh, "Жақсылықов", "zhaksylykov"},
+		{translit.TranslitKazakh, "Нұрсұлтан Әбішұлы", "nursultan abishuly"},
+		{translit.TranslitKazakh, "Өтеғали Һәкімов", "otegali hakimov"},
+		{translit.TranslitSerbian, "Новак Ђоковић", "novak đoković"},
+		{translit.TranslitSerbian, "Љубиша Џајић", "ljubiša džajić"},
+		{translit.TranslitSerbian, "Његош Јанковић", "njegoš janković"},
+		{translit.TranslitBulgarian, "Христо Стоичков", "hristo stoichkov"},
+		{translit.TranslitBulgarian, "Щерю Ъглев", "shteryu aglev"},
+		{translit.TranslitBulgarian, "Мария Иванова", "maria ivanova"},
+	}
+	for _, tc := range cases {
+		if got := tc.fn(tc.cyrillic); got != tc.expected {
+			t.Errorf("Транслитерация %q: ожидалось %q, получено %q", tc.cyrillic, tc.expected, got)
+		}
+	}
+
+	// Имя на казахском сравнивается через казахский стандарт, а не проходит без транслитерации
+	result := matcher.MatchNames("Жақсылықов Әлібек", "Zhaksylykov Alibek", nil, nil)
+	if result.BestMatch1 != "zhaksylykov alibek" || result.LevenshteinScore != 1 {
+		t.Errorf("Ожидалась транслитерация по казахскому стандарту, получено %q %v", result.BestMatch1, result.BestMatch1Standards)
+	}
+
+	// Подсказка языка включает стандарт, который нельзя определить по буквам
+	cfg := matcher.DefaultConfig()
+	hinted := cfg
+	hinted.LanguageHint = translit.LanguageUkrainian
+	plain := matcher.MatchNames("Ольга Шевченко", "Olha Shevchenko", nil, &cfg)
+	withHint := matcher.MatchNames("Ольга Шевченко", "Olha Shevchenko", nil, &hinted)
+	if withHint.LevenshteinScore != 1 || withHint.Score <= plain.Score {
+		t.Errorf("Подсказка языка должна улучшать оценку: %d (%v) против %d", withHint.Score, withHint.BestMatch1Standards, plain.Score)
+	}
+
+	// Казахская фамилия совпадает с паспортным написанием и по фонетическим оценкам,
+	// в том числе при сравнении имен по частям
+	kazakh := matcher.DefaultConfig()
+	kazakh.LanguageHint = translit.LanguageKazakh
+	kazakhMatcher := matcher.NewNameMatcher(&kazakh)
+	inputs := [][2]matcher.NameInput{
+		{{Text: "Жақсылықов"}, {Text: "Zhaksylykov"}},
+		{{Text: "Жақсылықов Әлібек"}, {Text: "ZHAKSYLYKOV ALIBEK"}},
+		{{Parts: &matcher.StructuredName{Last: "Жақсылықов", First: "Әлібек"}}, {Parts: &matcher.StructuredName{Last: "Zhaksylykov", First: "Alibek"}}},
+	}
+	for _, pair := range inputs {
+		result, err := kazakhMatcher.MatchInputs(context.Background(), pair[0], pair[1], nil)
+		if err != nil || result.MatchType != "match" {
+			t.Errorf("%+v <-> %+v: ожидалось совпадение, получено %d %s (фонетика %.2f, DoubleMetaphone %.2f), ошибка %v",
+				pair[0], pair[1], result.Score, result.MatchType, result.PhoneticScore, result.DoubleMetaphoneScore, err)
+		}
+	}
+}
+
+// TestDetectScripts проверяет определение алфавитов имени и сравнение смешанных имен
+func TestDetectScripts(t *testing.T) {
+	mixed := translit.DetectScripts("Ivanov Иван")
+	if mixed.Dominant != translit.ScriptLatin || len(mixed.Scripts) != 2 || len(mixed.MixedTokens) != 0 || mixed.HomoglyphSuspected {
+		t.Errorf("Ivanov Иван: неверные сведения об алфавитах %+v", mixed)
+	}
+
+	accented := translit.DetectScripts("Émile Zola")
+	if accented.Dominant != translit.ScriptLatin || !accented.SameScripts(translit.DetectScripts("Emile Zola")) {
+		t.Errorf("Émile Zola: ожидалась латиница, получено %+v", accented)
+	}
+
+	// Латинская "a" в кириллическом слове
+	homoglyph := translit.DetectScripts("Ивaнов Иван")
+	if homoglyph.Dominant != translit.ScriptCyrillic || len(homoglyph.MixedTokens) != 1 || !homoglyph.HomoglyphSuspected {
+		t.Errorf("Ивaнов Иван: ожидалось подозрение на двойников, получено %+v", homoglyph)
+	}
+
+	// Смешение без двойников (ж не похожа на латинскую букву)
+	if info := translit.DetectScripts("Ivanжov"); len(info.MixedTokens) != 1 || info.HomoglyphSuspected {
+		t.Errorf("Ivanжov: неверные сведения об алфавитах %+v", info)
+	}
+
+	if greek := translit.DetectScripts("Ξενοφών"); greek.Dominant != translit.ScriptGreek {
+		t.Errorf("Ξενοφών: ожидался греческий алфавит, получено %q", greek.Dominant)
+	}
+	if empty := translit.DetectScripts("123"); empty.Dominant != "" || len(empty.Scripts) != 0 {
+		t.Errorf("123: ожидалось отсутствие алфавитов, получено %+v", empty)
+	}
+
+	// Смешанное имя сравнивается с кириллическим через транслитерацию в обоих алгоритмах
+	strategyConfig := matcher.DefaultConfig()
+	strategyConfig.Pipeline = matcher.PipelineStrategy
+	for _, cfg := range []matcher.Config{matcher.DefaultConfig(), strategyConfig} {
+		result := matcher.MatchNames("Ivanov Иван", "Иванов Иван", nil, &cfg)
+		if result.MatchType != "match" {
+			t.Errorf("%s: ожидалось совпадение Ivanov Иван и Иванов Иван, получено %d (%s)", cfg.Pipeline, result.Score, result.MatchType)
+		}
+	}
+}
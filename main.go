@@ -8,17 +8,57 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/x0rium/compareNames/api"
+	"github.com/x0rium/compareNames/matcher/translit"
+	"github.com/x0rium/compareNames/matcher/utils"
 )
 
 func main() {
 	// Парсим аргументы командной строки
 	port := flag.Int("port", 8080, "HTTP server port")
+	writeTimeout := flag.Duration("write-timeout", 2*time.Minute, "HTTP write timeout (must cover large batch requests)")
+	translitTables := flag.String("translit-tables", "", "Comma-separated JSON transliteration tables to register")
+	nicknameDicts := flag.String("nickname-dicts", "", "Comma-separated JSON or CSV nickname dictionaries to load")
+	dictReload := flag.Duration("dict-reload", 0, "Interval for reloading changed nickname dictionaries (0 disables)")
 	flag.Parse()
 
+	// Регистрируем дополнительные стандарты транслитерации
+	if *translitTables != "" {
+		for _, path := range strings.Split(*translitTables, ",") {
+			name, err := translit.RegisterTableFile(strings.TrimSpace(path))
+			if err != nil {
+				log.Fatalf("Ошибка загрузки таблицы транслитерации: %v", err)
+			}
+			log.Printf("Зарегистрирован стандарт транслитерации %q", name)
+		}
+	}
+
+	// Загружаем словари имен и при необходимости следим за их изменениями
+	if *nicknameDicts != "" {
+		paths := strings.Split(*nicknameDicts, ",")
+		for i := range paths {
+			paths[i] = strings.TrimSpace(paths[i])
+		}
+		if err := utils.Dictionary().LoadFiles(paths...); err != nil {
+			log.Fatalf("Ошибка загрузки словаря имен: %v", err)
+		}
+		log.Printf("Загружены словари имен: %s", strings.Join(paths, ", "))
+
+		if *dictReload > 0 {
+			go utils.Dictionary().Watch(context.Background(), *dictReload, func(err error) {
+				if err != nil {
+					log.Printf("Ошибка перезагрузки словаря имен: %v", err)
+					return
+				}
+				log.Printf("Словари имен перезагружены")
+			}, paths...)
+		}
+	}
+
 	// Настраиваем роуты
 	router := api.SetupRoutes()
 
@@ -27,7 +67,7 @@ func main() {
 		Addr:         fmt.Sprintf(":%d", *port),
 		Handler:      router,
 		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
+		WriteTimeout: *writeTimeout,
 		IdleTimeout:  30 * time.Second,
 	}
 
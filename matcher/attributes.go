@@ -0,0 +1,150 @@
+package matcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/x0rium/compareNames/matcher/similarity"
+)
+
+// dateLayouts поддерживаемые форматы дат для компаратора ComparatorDate
+var dateLayouts = []string{"2006-01-02", "02.01.2006", "02/01/2006", "20060102"}
+
+// UnmarshalJSON разбирает атрибут как объект {"match", "value1", "value2"}
+// или как логическое значение, равнозначное {"match": true|false}
+func (a *Attribute) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] != '{' {
+		return json.Unmarshal(data, &a.Match)
+	}
+
+	type attribute Attribute // Тип без метода UnmarshalJSON для исключения рекурсии
+	return json.Unmarshal(data, (*attribute)(a))
+}
+
+// hasValues проверяет, заданы ли у атрибута значения обеих сторон
+func (a Attribute) hasValues() bool {
+	return strings.TrimSpace(a.Value1) != "" && strings.TrimSpace(a.Value2) != ""
+}
+
+// attributeRule возвращает правило сравнения атрибута из конфигурации.
+// Для атрибутов без правила используется точное сравнение с весом 1
+func (m *NameMatcher) attributeRule(name string) AttributeRule {
+	if rule, ok := m.Config.AttributeRules[name]; ok {
+		return rule
+	}
+	return AttributeRule{Weight: 1, Comparator: ComparatorExact}
+}
+
+// attributesScore вычисляет взвешенную оценку совпадения атрибутов в диапазоне 0..1.
+// Возвращает false, если ни один атрибут не участвует в сравнении
+func (m *NameMatcher) attributesScore(attrs Attributes) (float64, bool) {
+	totalWeight := 0.0
+	totalScore := 0.0
+
+	for name, attr := range attrs {
+		// Атрибут только с одной заполненной стороной ничего не говорит о совпадении
+		if !attr.hasValues() && (attr.Value1 != "" || attr.Value2 != "") {
+			continue
+		}
+
+		rule := m.attributeRule(name)
+		if rule.Weight <= 0 {
+			continue
+		}
+
+		totalWeight += rule.Weight
+		totalScore += rule.Weight * compareAttribute(rule, attr)
+	}
+
+	if totalWeight == 0 {
+		return 0, false
+	}
+
+	return totalScore / totalWeight, true
+}
+
+// applyAttributes смешивает оценку имен (0..1) с оценкой атрибутов в пропорции
+// AdditionalAttrsWeight и сохраняет оценку атрибутов в результате.
+// Возвращает false, если атрибуты не повлияли на оценку
+func (m *NameMatcher) applyAttributes(score float64, attrs Attributes, result *MatchResult) (float64, bool) {
+	attrsScore, ok := m.attributesScore(attrs)
+	if !ok {
+		return score, false
+	}
+
+	result.AdditionalAttributesScore = math.Round(attrsScore*100) / 100
+
+	weight := m.Config.AdditionalAttrsWeight
+	return score*(1.0-weight) + attrsScore*weight, true
+}
+
+// compareAttribute сравнивает значения атрибута по правилу. Атрибут без значений
+// оценивается по готовому признаку Match
+func compareAttribute(rule AttributeRule, attr Attribute) float64 {
+	if !attr.hasValues() {
+		if attr.Match {
+			return 1.0
+		}
+		return 0.0
+	}
+
+	switch rule.Comparator {
+	case ComparatorDate:
+		return compareDates(attr.Value1, attr.Value2, rule.ToleranceDays)
+	case ComparatorFuzzy:
+		return similarity.JaroWinklerSimilarity(normalizeAttributeValue(attr.Value1), normalizeAttributeValue(attr.Value2))
+	default:
+		if normalizeAttributeValue(attr.Value1) == normalizeAttributeValue(attr.Value2) {
+			return 1.0
+		}
+		return 0.0
+	}
+}
+
+// compareDates сравнивает даты с допуском в днях. Если дату не удалось разобрать,
+// значения сравниваются как строки
+func compareDates(value1, value2 string, toleranceDays int) float64 {
+	date1, ok1 := parseDate(value1)
+	date2, ok2 := parseDate(value2)
+	if !ok1 || !ok2 {
+		if normalizeAttributeValue(value1) == normalizeAttributeValue(value2) {
+			return 1.0
+		}
+		return 0.0
+	}
+
+	diff := date1.Sub(date2)
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff <= time.Duration(toleranceDays)*24*time.Hour {
+		return 1.0
+	}
+	return 0.0
+}
+
+// parseDate разбирает дату в одном из поддерживаемых форматов
+func parseDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// normalizeAttributeValue приводит значение к нижнему регистру и удаляет пробелы и дефисы,
+// чтобы "AB 123-456" и "ab123456" считались одинаковыми
+func normalizeAttributeValue(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '\t' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(value)))
+}
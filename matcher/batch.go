@@ -0,0 +1,77 @@
+package matcher
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// ErrEmptyName ошибка сравнения пары, в которой не указано одно из имен
+var ErrEmptyName = errors.New("both name1 (or name1_parts) and name2 (or name2_parts) are required")
+
+// NamePair пара имен для пакетного сравнения
+type NamePair struct {
+	Name1      string          `json:"name1"`
+	Name2      string          `json:"name2"`
+	Name1Parts *StructuredName `json:"name1_parts,omitempty"` // Первое имя по частям вместо Name1
+	Name2Parts *StructuredName `json:"name2_parts,omitempty"` // Второе имя по частям вместо Name2
+	Attributes Attributes      `json:"attributes,omitempty"`
+}
+
+// BatchResult результат сравнения одной пары из пакета
+type BatchResult struct {
+	Result MatchResult
+	Err    error
+}
+
+// MatchBatch сравнивает пары имен конкурентно, используя не более workers горутин
+// (при workers <= 0 - по числу процессоров). Результаты возвращаются в порядке
+// входных пар, ошибка отдельной пары не прерывает обработку остальных
+func (m *NameMatcher) MatchBatch(ctx context.Context, pairs []NamePair, workers int) []BatchResult {
+	results := make([]BatchResult, len(pairs))
+
+	runParallel(len(pairs), workers, func(i int) {
+		pair := pairs[i]
+		name1 := NameInput{Text: pair.Name1, Parts: pair.Name1Parts}
+		name2 := NameInput{Text: pair.Name2, Parts: pair.Name2Parts}
+		if name1.IsEmpty() || name2.IsEmpty() {
+			results[i].Err = ErrEmptyName
+			return
+		}
+
+		results[i].Result, results[i].Err = m.MatchInputs(ctx, name1, name2, pair.Attributes)
+	})
+
+	return results
+}
+
+// runParallel вызывает fn для индексов 0..n-1 в пуле из не более чем workers горутин
+func runParallel(n, workers int, fn func(i int)) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > n {
+		workers = n
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+
+	wg.Wait()
+}
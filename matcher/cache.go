@@ -2,8 +2,11 @@ package matcher
 
 import (
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/x0rium/compareNames/matcher/utils"
 )
 
 // NewCache создает новый экземпляр кэша
@@ -39,6 +42,15 @@ func (c *Cache) Put(key string, result MatchResult) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	// Если ключ уже есть в кэше (например, после конкурентных промахов), обновляем значение
+	if _, ok := c.items[key]; ok {
+		c.items[key] = CacheItem{
+			Result:     result,
+			CreateTime: time.Now(),
+		}
+		return
+	}
+
 	// Если кэш достиг максимального размера, удаляем самый старый элемент (LRU)
 	if len(c.items) >= c.maxSize {
 		if len(c.keys) > 0 {
@@ -73,14 +85,21 @@ func (c *Cache) Update(key string) {
 	}
 }
 
+// cacheOrderReversed проверяет, идут ли имена в обратном порядке относительно ключа кэша
+func cacheOrderReversed(name1, name2 string) bool {
+	return strings.ToLower(name1) > strings.ToLower(name2)
+}
+
 // Генерирует ключ для кэша результатов сравнения
 func (m *NameMatcher) cacheKey(name1, name2 string, attrs Attributes) string {
 	// Сортируем имена для обеспечения одинакового ключа независимо от порядка аргументов
 	names := []string{strings.ToLower(name1), strings.ToLower(name2)}
 	sort.Strings(names)
 
-	// Формируем базовый ключ из имен
-	key := names[0] + "||" + names[1] + "||"
+	// Формируем базовый ключ из имен. Версия словаря имен сбрасывает результаты,
+	// полученные до изменения словаря. Имена и значения атрибутов берутся в кавычки,
+	// чтобы разделители внутри значений не давали одинаковых ключей
+	key := strconv.Quote(names[0]) + "||" + strconv.Quote(names[1]) + "||dict:" + strconv.FormatUint(utils.Dictionary().Version(), 10) + "||"
 
 	// Если есть дополнительные атрибуты, добавляем их к ключу
 	if attrs != nil {
@@ -93,10 +112,16 @@ func (m *NameMatcher) cacheKey(name1, name2 string, attrs Attributes) string {
 
 		// Добавляем атрибуты к ключу
 		for _, name := range attrNames {
-			if attrs[name].Match {
-				key += name + ":true||"
+			attr := attrs[name]
+			if attr.Value1 != "" || attr.Value2 != "" {
+				// Компараторы симметричны, поэтому порядок значений не важен
+				values := []string{attr.Value1, attr.Value2}
+				sort.Strings(values)
+				key += strconv.Quote(name) + ":" + strconv.Quote(values[0]) + "|" + strconv.Quote(values[1]) + "||"
+			} else if attr.Match {
+				key += strconv.Quote(name) + ":true||"
 			} else {
-				key += name + ":false||"
+				key += strconv.Quote(name) + ":false||"
 			}
 		}
 	}
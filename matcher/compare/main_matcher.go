@@ -110,16 +110,26 @@ func MatchNames(name1, name2 string, attrs MatchAttributes, matcher NameMatcher)
 		}
 	}
 
-	// 4. Проверка на разные языки (кириллица/латиница)
-	isName1Cyrillic := translit.IsCyrillic(processedName1)
-	isName2Cyrillic := translit.IsCyrillic(processedName2)
+	// 4. Проверка на разные алфавиты: смешанное имя ("Ivanov Иван") отличается от имени на одном алфавите.
+	// Транслитерируется имя с большей долей кириллицы
+	scripts1 := translit.DetectScripts(processedName1)
+	scripts2 := translit.DetectScripts(processedName2)
+	isName1Cyrillic := scripts1.Share(translit.ScriptCyrillic) > scripts2.Share(translit.ScriptCyrillic)
+
+	// 5. Выбираем стратегию сравнения в зависимости от алфавитов имен
+	if !scripts1.SameScripts(scripts2) {
+		// Кириллические слова смешанного имени на латинской стороне транслитерируем заранее
+		differentName1, differentName2 := processedName1, processedName2
+		if isName1Cyrillic {
+			differentName2 = latinizeCyrillicWords(differentName2)
+		} else {
+			differentName1 = latinizeCyrillicWords(differentName1)
+		}
 
-	// 5. Выбираем стратегию сравнения в зависимости от языка имен
-	if isName1Cyrillic != isName2Cyrillic {
 		// Сравнение имен на разных алфавитах
 		differentAlphabetsResult := CompareDifferentAlphabets(
-			processedName1,
-			processedName2,
+			differentName1,
+			differentName2,
 			isName1Cyrillic,
 			startTime,
 			matcher.GetNameVariations,
@@ -146,6 +156,17 @@ func MatchNames(name1, name2 string, attrs MatchAttributes, matcher NameMatcher)
 	return convertSameAlphabetResult(sameAlphabetResult)
 }
 
+// latinizeCyrillicWords транслитерирует по ГОСТ кириллические слова имени, остальные оставляет как есть
+func latinizeCyrillicWords(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		if translit.IsCyrillic(word) {
+			words[i] = translit.TranslitGOST(word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
 // convertSameAlphabetResult преобразует SameAlphabetResult в MatchResult
 func convertSameAlphabetResult(result SameAlphabetResult) MatchResult {
 	return MatchResult{
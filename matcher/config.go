@@ -8,6 +8,46 @@ const (
 	CacheSize             = 1000 // Максимальный размер кэша результатов
 )
 
+// Алгоритмы (конвейеры) сравнения имен
+const (
+	// PipelineLegacyBonus взвешенные метрики по перестановкам и транслитерациям с бонусами
+	PipelineLegacyBonus = "legacy_bonus"
+	// PipelineStrategy стратегии пакета compare (инициалы, разные алфавиты, сравнение по частям)
+	PipelineStrategy = "strategy"
+)
+
+// Фонетические кодировщики для фонетической оценки
+const (
+	// PhoneticSoundex Soundex; кириллица предварительно транслитерируется по ГОСТ
+	PhoneticSoundex = "soundex"
+	// PhoneticRussian русский фонетический алгоритм similarity.RussianPhonetic
+	PhoneticRussian = "russian"
+	// PhoneticDoubleMetaphone первичный и вторичный коды Double Metaphone
+	PhoneticDoubleMetaphone = "double_metaphone"
+	// PhoneticNYSIIS алгоритм NYSIIS
+	PhoneticNYSIIS = "nysiis"
+	// PhoneticCaverphone алгоритм Caverphone 2.0
+	PhoneticCaverphone = "caverphone"
+	// PhoneticDaitchMokotoff Daitch-Mokotoff Soundex для восточноевропейских и еврейских фамилий
+	PhoneticDaitchMokotoff = "daitch_mokotoff"
+	// PhoneticBeiderMorse упрощенный Beider-Morse Phonetic Matching
+	PhoneticBeiderMorse = "beider_morse"
+)
+
+// Компараторы дополнительных атрибутов
+const (
+	ComparatorExact = "exact" // Точное совпадение без учета регистра, пробелов и дефисов
+	ComparatorDate  = "date"  // Совпадение дат с допуском ToleranceDays
+	ComparatorFuzzy = "fuzzy" // Сходство строк по Джаро-Винклеру
+)
+
+// AttributeRule правило сравнения дополнительного атрибута
+type AttributeRule struct {
+	Weight        float64 `json:"weight"`
+	Comparator    string  `json:"comparator"`
+	ToleranceDays int     `json:"tolerance_days,omitempty"`
+}
+
 // Config структура с настройками для алгоритма сравнения имен
 type Config struct {
 	// Веса для алгоритмов сравнения
@@ -28,10 +68,48 @@ type Config struct {
 	// Параметры транслитерации
 	EnableTransliteration    bool     `json:"enable_transliteration"`
 	TransliterationStandards []string `json:"transliteration_standards"`
+	// Язык кириллических имен (ru, uk, be, kk, sr, bg); пустое значение - определять по буквам
+	LanguageHint string `json:"language_hint"`
+
+	// Фонетический кодировщик: PhoneticSoundex (по умолчанию), PhoneticRussian и другие
+	// кодировщики из реестра similarity.LookupEncoder
+	PhoneticEncoder string `json:"phonetic_encoder"`
+	// Набор фонетических кодировщиков с весами. Если задан, фонетическая оценка -
+	// взвешенное среднее оценок кодировщиков, а PhoneticEncoder не используется
+	PhoneticEncoders map[string]float64 `json:"phonetic_encoders,omitempty"`
 
 	// Параметры перестановки
 	EnableNamePartPermutation bool `json:"enable_name_part_permutation"`
 
+	// Морфология: приведение фамилий, имен и отчеств к именительному падежу
+	// ("Иванову Ивану" - "Иванов Иван")
+	MorphologyNormalization bool `json:"morphology_normalization"`
+	// Мужская и женская формы фамилии ("Иванов" - "Иванова") сравниваются как одна фамилия семьи
+	// с отметкой SameFamily, но совпадением одного человека не считаются: оценка ограничивается
+	// возможным совпадением
+	GenderPairedSurnamesAsFamily bool `json:"gender_paired_surnames_as_family"`
+	// Доля, на которую снижается оценка, если имена указывают на разный пол
+	// ("Александр Иванов" - "Александра Иванова"); такие имена не больше чем возможное совпадение.
+	// 0 - не снижать
+	GenderConflictPenalty float64 `json:"gender_conflict_penalty"`
+	// Бонус за межъязыковое соответствие имен ("Пётр" - "Pierre", "Иосиф" - "José"),
+	// умножается на оценку CognateScore; 0 - не учитывать
+	CognateBonus float64 `json:"cognate_bonus"`
+	// Оценка имени на другом языке, замененного формой из первого имени ("Joseph Brodsky" -
+	// "Иосиф Brodsky"): оценка такого сравнения умножается на 1 - (1 - CognateWeight) / N, где N -
+	// число частей имени, и используется, если она выше исходной; 0 - не заменять
+	CognateWeight float64 `json:"cognate_weight"`
+	// Оценка уменьшительной формы имени из словаря, замененной полной формой из первого имени
+	// ("Саша Петров" - "Александр Петров"), по тем же правилам, что CognateWeight. Заменяются только
+	// формы на одном алфавите; 0 - не заменять
+	NicknameWeight float64 `json:"nickname_weight"`
+
+	// Правила сравнения дополнительных атрибутов по их названиям
+	AttributeRules map[string]AttributeRule `json:"attribute_rules"`
+
+	// Алгоритм сравнения: PipelineLegacyBonus (по умолчанию) или PipelineStrategy
+	Pipeline string `json:"pipeline"`
+
 	// Другие параметры
 	NGramSize     int  `json:"ngram_size"`
 	EnableCaching bool `json:"enable_caching"`
@@ -47,8 +125,8 @@ func DefaultConfig() Config {
 		JaroWinklerWeight:     0.3,
 		PhoneticWeight:        0.3,
 		DoubleMetaphoneWeight: 0.2,
-		CosineWeight:          0.0, // Не используется в базовой версии
-		AdditionalAttrsWeight: 0.0, // Не используется в базовой версии
+		CosineWeight:          0.0,  // Не используется в базовой версии
+		AdditionalAttrsWeight: 0.15, // Доля оценки атрибутов, если они переданы
 
 		// Пороговые значения
 		JaroWinklerThreshold:   0.85,
@@ -59,11 +137,33 @@ func DefaultConfig() Config {
 
 		// Параметры транслитерации
 		EnableTransliteration:    true,
-		TransliterationStandards: []string{"gost", "iso9", "bgnpcgn", "ungegn"},
+		TransliterationStandards: []string{"gost", "iso9", "bgnpcgn", "ungegn", "icao"},
+
+		// Фонетический кодировщик
+		PhoneticEncoder: PhoneticSoundex,
 
 		// Параметры перестановки
 		EnableNamePartPermutation: true,
 
+		// Морфология
+		MorphologyNormalization: true,
+		GenderConflictPenalty:   0.1,
+		CognateBonus:            0.12,
+		CognateWeight:           0.9,
+		NicknameWeight:          0.6,
+
+		// Правила сравнения атрибутов
+		AttributeRules: map[string]AttributeRule{
+			"birth_date":      {Weight: 3, Comparator: ComparatorDate},
+			"document_number": {Weight: 3, Comparator: ComparatorExact},
+			"gender":          {Weight: 1, Comparator: ComparatorExact},
+			"country":         {Weight: 1, Comparator: ComparatorExact},
+			"citizenship":     {Weight: 1, Comparator: ComparatorExact},
+		},
+
+		// Алгоритм сравнения
+		Pipeline: PipelineLegacyBonus,
+
 		// Другие параметры
 		NGramSize:     3,
 		EnableCaching: true,
@@ -71,3 +171,13 @@ func DefaultConfig() Config {
 		EnableLogging: true,
 	}
 }
+
+// Методы compare.ConfigProvider для использования конфигурации в стратегиях пакета compare
+
+func (c Config) GetLevenshteinWeight() float64     { return c.LevenshteinWeight }
+func (c Config) GetJaroWinklerWeight() float64     { return c.JaroWinklerWeight }
+func (c Config) GetPhoneticWeight() float64        { return c.PhoneticWeight }
+func (c Config) GetDoubleMetaphoneWeight() float64 { return c.DoubleMetaphoneWeight }
+func (c Config) GetCosineWeight() float64          { return c.CosineWeight }
+func (c Config) GetAdditionalAttrsWeight() float64 { return c.AdditionalAttrsWeight }
+func (c Config) GetNGramSize() int                 { return c.NGramSize }
@@ -0,0 +1,196 @@
+package matcher
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/x0rium/compareNames/matcher/translit"
+	"github.com/x0rium/compareNames/matcher/utils"
+)
+
+// Названия бонусов в пояснении к оценке
+const (
+	BonusTransliteration = "transliteration" // Имена на разных алфавитах
+	BonusPermutation     = "permutation"     // Перестановка частей ФИО
+	BonusInitials        = "initials"        // Инициалы вместо полных имен
+	BonusHyphen          = "hyphen"          // Двойная фамилия через дефис
+	BonusNameForm        = "name_form"       // Уменьшительная/альтернативная форма имени
+	BonusCognate         = "cognate"         // Соответствие имени на другом языке
+)
+
+// Виды замены формы имени в пояснении к оценке
+const (
+	SubstitutionCognate  = "cognate"  // Имя на другом языке
+	SubstitutionNickname = "nickname" // Уменьшительная форма имени из словаря
+)
+
+// Роли частей имени в пояснении к оценке
+const (
+	roleSurname    = utils.RoleSurname
+	roleGiven      = utils.RoleGiven
+	rolePatronymic = utils.RolePatronymic
+	roleInitial    = utils.RoleInitial
+)
+
+// alignParts сопоставляет части двух имен попарно, начиная с наиболее похожих пар.
+// Если роли частей обоих имен определены уверенно, сопоставляются только части
+// с совместимыми ролями (фамилия с фамилией, инициал с именем).
+// Результат упорядочен по частям первого имени, части второго имени без пары идут в конце
+func (m *NameMatcher) alignParts(parsed1, parsed2 utils.ParsedName) []AlignedPart {
+	parts1, roles1 := partRoles(parsed1)
+	parts2, roles2 := partRoles(parsed2)
+	byRole := parsed1.Confident && parsed2.Confident
+
+	type pair struct {
+		i, j  int
+		score float64
+	}
+
+	pairs := make([]pair, 0, len(parts1)*len(parts2))
+	for i, p1 := range parts1 {
+		for j, p2 := range parts2 {
+			if byRole && !compatibleRoles(roles1[i], roles2[j]) {
+				continue
+			}
+			pairs = append(pairs, pair{i, j, m.partSimilarity(p1, p2)})
+		}
+	}
+	sort.SliceStable(pairs, func(a, b int) bool {
+		return pairs[a].score > pairs[b].score
+	})
+
+	matched1 := make([]int, len(parts1))
+	for i := range matched1 {
+		matched1[i] = -1
+	}
+	scores := make([]float64, len(parts1))
+	used2 := make([]bool, len(parts2))
+	for _, p := range pairs {
+		if matched1[p.i] >= 0 || used2[p.j] {
+			continue
+		}
+		matched1[p.i] = p.j
+		scores[p.i] = p.score
+		used2[p.j] = true
+	}
+
+	aligned := make([]AlignedPart, 0, len(parts1)+len(parts2))
+	for i, p1 := range parts1 {
+		part := AlignedPart{Role: roles1[i], Part1: p1}
+		if j := matched1[i]; j >= 0 {
+			part.Part2 = parts2[j]
+			part.Score = math.Round(scores[i]*100) / 100
+			// Роль инициала уточняем по второй части
+			if part.Role == roleInitial {
+				part.Role = roles2[j]
+			}
+		}
+		aligned = append(aligned, part)
+	}
+	for j, p2 := range parts2 {
+		if !used2[j] {
+			aligned = append(aligned, AlignedPart{Role: roles2[j], Part2: p2})
+		}
+	}
+
+	return aligned
+}
+
+// partRoles возвращает тексты и роли частей разобранного имени
+func partRoles(parsed utils.ParsedName) ([]string, []string) {
+	parts := make([]string, len(parsed.Parts))
+	roles := make([]string, len(parsed.Parts))
+	for i, part := range parsed.Parts {
+		parts[i], roles[i] = part.Text, part.Role
+	}
+	return parts, roles
+}
+
+// compatibleRoles проверяет, можно ли сопоставить части с указанными ролями.
+// Инициал сопоставляется с именем, отчеством или другим инициалом
+func compatibleRoles(role1, role2 string) bool {
+	if role1 == role2 {
+		return true
+	}
+	isGivenLike := func(role string) bool {
+		return role == roleGiven || role == rolePatronymic || role == roleInitial
+	}
+	return (role1 == roleInitial && isGivenLike(role2)) || (role2 == roleInitial && isGivenLike(role1))
+}
+
+// partSimilarity вычисляет сходство двух частей имени с учетом транслитерации.
+// Инициал сравнивается с первой буквой другой части
+func (m *NameMatcher) partSimilarity(part1, part2 string) float64 {
+	if isInitialPart(part1) || isInitialPart(part2) {
+		if firstLatinLetter(part1) == firstLatinLetter(part2) {
+			return 1.0
+		}
+		return 0.0
+	}
+
+	// Части на одном алфавите сравниваем без транслитерации
+	if translit.SameScripts(part1, part2) {
+		return jaroWinklerSimilarity(strings.ToLower(part1), strings.ToLower(part2))
+	}
+
+	best := 0.0
+	for _, v1 := range m.transliterations(part1) {
+		for _, v2 := range m.transliterations(part2) {
+			if score := jaroWinklerSimilarity(strings.ToLower(v1.Text), strings.ToLower(v2.Text)); score > best {
+				best = score
+			}
+		}
+	}
+	return best
+}
+
+// splitParts разбивает имя на части по пробелам, дефисам, запятым и точкам инициалов,
+// как utils.ParseName
+func splitParts(name string) []string {
+	return strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '.' || r == '\t' || r == ','
+	})
+}
+
+// isInitialPart проверяет, является ли часть имени инициалом ("И" или "И.")
+func isInitialPart(part string) bool {
+	return utf8.RuneCountInString(strings.Trim(part, ".")) == 1
+}
+
+// firstLatinLetter возвращает первую букву части имени после транслитерации по ГОСТ
+func firstLatinLetter(part string) string {
+	part = strings.ToLower(strings.Trim(part, "."))
+	if translit.IsCyrillic(part) {
+		part = translit.TranslitGOST(part)
+	}
+	if part == "" {
+		return ""
+	}
+	r, _ := utf8.DecodeRuneInString(part)
+	return string(r)
+}
+
+// swapped возвращает результат с переставленными сторонами сравнения
+func (r MatchResult) swapped() MatchResult {
+	r.BestMatch1, r.BestMatch2 = r.BestMatch2, r.BestMatch1
+	r.BestMatch1Standards, r.BestMatch2Standards = r.BestMatch2Standards, r.BestMatch1Standards
+
+	if r.Explanation != nil {
+		explanation := *r.Explanation
+		explanation.AlignedParts = make([]AlignedPart, len(r.Explanation.AlignedParts))
+		for i, part := range r.Explanation.AlignedParts {
+			part.Part1, part.Part2 = part.Part2, part.Part1
+			explanation.AlignedParts[i] = part
+		}
+		if r.Explanation.Substitution != nil {
+			substitution := *r.Explanation.Substitution
+			substitution.Part1, substitution.Part2 = substitution.Part2, substitution.Part1
+			explanation.Substitution = &substitution
+		}
+		r.Explanation = &explanation
+	}
+
+	return r
+}
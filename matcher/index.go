@@ -0,0 +1,261 @@
+package matcher
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/x0rium/compareNames/matcher/similarity"
+	"github.com/x0rium/compareNames/matcher/translit"
+)
+
+// NameIndex индекс имен в памяти для поиска кандидатов без полного перебора.
+// Каждое имя раскладывается на ключи блокировки (фонетические коды, триграммы и первые
+// буквы частей имени), и полное сравнение выполняется только с именами из общих блоков
+type NameIndex struct {
+	matcher *NameMatcher
+	names   map[string]string              // Имена по идентификаторам
+	keys    map[string][]string            // Ключи блокировки по идентификаторам
+	blocks  map[string]map[string]struct{} // Идентификаторы по ключам блокировки
+	mutex   sync.RWMutex
+}
+
+// IndexMatch найденное в индексе имя с результатом сравнения
+type IndexMatch struct {
+	ID     string      `json:"id"`
+	Name   string      `json:"name"`
+	Result MatchResult `json:"result"`
+}
+
+// indexEntry запись индекса в сохраненном виде
+type indexEntry struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// NewNameIndex создает пустой индекс имен с указанной конфигурацией сравнения
+func NewNameIndex(cfg *Config) *NameIndex {
+	return &NameIndex{
+		matcher: NewNameMatcher(cfg),
+		names:   make(map[string]string),
+		keys:    make(map[string][]string),
+		blocks:  make(map[string]map[string]struct{}),
+	}
+}
+
+// Add добавляет имя в индекс. Имя с уже существующим идентификатором заменяется
+func (idx *NameIndex) Add(id, name string) {
+	keys := blockingKeys(name)
+
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	idx.remove(id)
+
+	idx.names[id] = name
+	idx.keys[id] = keys
+	for _, key := range keys {
+		block, ok := idx.blocks[key]
+		if !ok {
+			block = make(map[string]struct{})
+			idx.blocks[key] = block
+		}
+		block[id] = struct{}{}
+	}
+}
+
+// Remove удаляет имя из индекса. Возвращает false, если идентификатор не найден
+func (idx *NameIndex) Remove(id string) bool {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	return idx.remove(id)
+}
+
+// remove удаляет имя из индекса; вызывается под блокировкой записи
+func (idx *NameIndex) remove(id string) bool {
+	if _, ok := idx.names[id]; !ok {
+		return false
+	}
+
+	for _, key := range idx.keys[id] {
+		delete(idx.blocks[key], id)
+		if len(idx.blocks[key]) == 0 {
+			delete(idx.blocks, key)
+		}
+	}
+	delete(idx.keys, id)
+	delete(idx.names, id)
+
+	return true
+}
+
+// Len возвращает количество имен в индексе
+func (idx *NameIndex) Len() int {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	return len(idx.names)
+}
+
+// Query возвращает до k имен, наиболее похожих на name (при k <= 0 - все найденные),
+// упорядоченных по убыванию оценки. Полное сравнение выполняется только для имен,
+// имеющих с name общий ключ блокировки. Ключи проверяются по уровням (см. blockingTiers):
+// следующий уровень используется, только если предыдущие не дали кандидатов
+func (idx *NameIndex) Query(name string, k int) []IndexMatch {
+	keys := blockingKeys(name)
+
+	// Собираем кандидатов из общих блоков
+	idx.mutex.RLock()
+	seen := make(map[string]struct{})
+	var ids, names []string
+	for _, prefixes := range blockingTiers {
+		for _, key := range keys {
+			if !hasAnyPrefix(key, prefixes) {
+				continue
+			}
+			for id := range idx.blocks[key] {
+				if _, ok := seen[id]; ok {
+					continue
+				}
+				seen[id] = struct{}{}
+				ids = append(ids, id)
+				names = append(names, idx.names[id])
+			}
+		}
+		if len(ids) > 0 {
+			break
+		}
+	}
+	idx.mutex.RUnlock()
+
+	candidates, _ := idx.matcher.FindBestMatches(context.Background(), name, names, k, 0)
+
+	matches := make([]IndexMatch, len(candidates))
+	for i, candidate := range candidates {
+		matches[i] = IndexMatch{
+			ID:     ids[candidate.Index],
+			Name:   candidate.Name,
+			Result: candidate.Result,
+		}
+	}
+
+	// Порядок кандидатов из карт случаен, поэтому при равных оценках упорядочиваем по идентификатору
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Result.Score != matches[j].Result.Score {
+			return matches[i].Result.Score > matches[j].Result.Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	return matches
+}
+
+// Save сохраняет содержимое индекса в формате JSON
+func (idx *NameIndex) Save(w io.Writer) error {
+	idx.mutex.RLock()
+	entries := make([]indexEntry, 0, len(idx.names))
+	for id, name := range idx.names {
+		entries = append(entries, indexEntry{ID: id, Name: name})
+	}
+	idx.mutex.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+
+	return json.NewEncoder(w).Encode(entries)
+}
+
+// LoadNameIndex загружает индекс, сохраненный методом Save, и заново вычисляет ключи блокировки
+func LoadNameIndex(r io.Reader, cfg *Config) (*NameIndex, error) {
+	var entries []indexEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	idx := NewNameIndex(cfg)
+	for _, entry := range entries {
+		idx.Add(entry.ID, entry.Name)
+	}
+
+	return idx, nil
+}
+
+// blockingTiers уровни ключей блокировки по префиксам: фонетические коды, затем триграммы,
+// затем первые буквы. Блоки первых букв велики, поэтому используются последними
+// (например, для запроса из одних инициалов)
+var blockingTiers = [][]string{{"sx:", "dm:"}, {"ng:"}, {"fl:"}}
+
+// hasAnyPrefix проверяет, начинается ли ключ с одного из префиксов
+func hasAnyPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// blockingKeys вычисляет ключи блокировки имени. Для каждой части имени,
+// приведенной к латинице по ГОСТ, используются код Soundex, коды Double Metaphone,
+// триграммы и первая буква, поэтому имена на разных алфавитах попадают в общие блоки.
+// Части без букв после транслитерации ("ъ", "ь") пропускаются
+func blockingKeys(name string) []string {
+	seen := make(map[string]struct{})
+	var keys []string
+
+	addKey := func(key string) {
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			keys = append(keys, key)
+		}
+	}
+
+	// Ключи строятся по имени с замененными буквами-двойниками, как и при сравнении
+	name, _ = translit.NormalizeHomoglyphs(name)
+
+	for _, part := range strings.Fields(strings.ToLower(strings.ReplaceAll(name, "-", " "))) {
+		part = strings.Trim(part, ".,'")
+		if part == "" {
+			continue
+		}
+
+		latin := part
+		if translit.IsCyrillic(part) {
+			latin = translit.TranslitGOST(part)
+		}
+
+		letters := []rune(latin)
+		if len(letters) == 0 {
+			continue
+		}
+		addKey("fl:" + string(letters[0]))
+
+		// Инициалы представлены только первой буквой
+		if len([]rune(part)) < 2 {
+			continue
+		}
+
+		if code := similarity.RussianSoundex(part); code != "0000" {
+			addKey("sx:" + code)
+		}
+
+		primary, secondary := similarity.DoubleMetaphone(latin)
+		if primary != "" {
+			addKey("dm:" + primary)
+		}
+		if secondary != "" {
+			addKey("dm:" + secondary)
+		}
+
+		for i := 0; i+3 <= len(letters); i++ {
+			addKey("ng:" + string(letters[i:i+3]))
+		}
+	}
+
+	return keys
+}
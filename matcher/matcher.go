@@ -1,36 +1,191 @@
 package matcher
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/x0rium/compareNames/matcher/similarity"
 	"github.com/x0rium/compareNames/matcher/translit"
+	"github.com/x0rium/compareNames/matcher/utils"
 )
 
 // MatchNames сравнивает два имени с указанной конфигурацией
-// Экспортированная функция для использования в других пакетах
+// Экспортированная функция для использования в других пакетах.
+// Каждый вызов начинается с пустого состояния; для серий сравнений
+// используйте долгоживущий NameMatcher и его метод Match
 func MatchNames(name1, name2 string, attrs Attributes, cfg *Config) MatchResult {
-	// Создаем экземпляр NameMatcher с указанной конфигурацией
-	_ = NewNameMatcher(cfg)
+	return newNameMatcher(cfg).match(name1, name2, attrs)
+}
+
+// Match сравнивает два имени с конфигурацией экземпляра.
+// Результаты сохраняются в кэше экземпляра и переиспользуются при повторных вызовах
+func (m *NameMatcher) Match(ctx context.Context, name1, name2 string, attrs Attributes) (MatchResult, error) {
+	return m.MatchInputs(ctx, NameInput{Text: name1}, NameInput{Text: name2}, attrs)
+}
+
+// MatchInputs сравнивает два имени, каждое из которых задано текстом или по частям.
+// Для имен по частям роли не угадываются: фамилия сравнивается с фамилией, имя с именем
+func (m *NameMatcher) MatchInputs(ctx context.Context, name1, name2 NameInput, attrs Attributes) (MatchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return MatchResult{}, err
+	}
+
+	// Без кэша просто выполняем сравнение
+	if m.cache == nil {
+		return m.matchInputs(name1, name2, attrs), nil
+	}
+
+	startTime := time.Now()
+	key := m.cacheKey(name1.cacheName(), name2.cacheName(), attrs)
+	reversed := cacheOrderReversed(name1.cacheName(), name2.cacheName())
+
+	// Проверяем кэш
+	if result, ok := m.cache.Get(key); ok {
+		m.cache.Update(key)
+		if reversed {
+			result = result.swapped()
+		}
+		result.FromCache = true
+		result.ProcessingTimeMS = time.Since(startTime).Milliseconds()
+		return result, nil
+	}
+
+	result := m.matchInputs(name1, name2, attrs)
+
+	// В кэше результат хранится в порядке имен из ключа
+	if reversed {
+		m.cache.Put(key, result.swapped())
+	} else {
+		m.cache.Put(key, result)
+	}
+
+	return result, nil
+}
+
+// match выполняет сравнение двух имен в свободной форме с конфигурацией экземпляра
+func (m *NameMatcher) match(name1, name2 string, attrs Attributes) MatchResult {
+	return m.matchInputs(NameInput{Text: name1}, NameInput{Text: name2}, attrs)
+}
+
+// matchInputs выполняет сравнение двух имен с конфигурацией экземпляра.
+// Перед сравнением буквы-двойники другого алфавита заменяются буквами алфавита слова,
+// а смешение алфавитов отмечается в результате как возможная подмена символов
+func (m *NameMatcher) matchInputs(input1, input2 NameInput, attrs Attributes) MatchResult {
+	name1, name2 := input1.text(), input2.text()
+	mixed := translit.DetectScripts(name1).MixedScript() || translit.DetectScripts(name2).MixedScript()
+	name1, normalized1 := translit.NormalizeHomoglyphs(name1)
+	name2, normalized2 := translit.NormalizeHomoglyphs(name2)
 
-	// Инициализируем результат
 	var result MatchResult
+	// Стратегии пакета compare выбираются явно в конфигурации
+	if m.Config.Pipeline == PipelineStrategy {
+		result = m.matchStrategy(name1, name2, attrs)
+	} else {
+		result = m.matchLegacy(name1, name2, input1.declared(), input2.declared(), attrs)
+		if word1, word2, score := cognatePair(name1, name2); score == 1 && m.Config.CognateWeight > 0 {
+			result = m.matchReplaced(result, SubstitutionCognate, word1, word2, m.Config.CognateWeight, name1, name2, input1, input2, attrs)
+		}
+		if word1, word2, ok := nicknamePair(name1, name2); ok && m.Config.NicknameWeight > 0 {
+			result = m.matchReplaced(result, SubstitutionNickname, word1, word2, m.Config.NicknameWeight, name1, name2, input1, input2, attrs)
+		}
 
-	// Проверяем точное совпадение
-	if strings.EqualFold(name1, name2) {
-		result.ExactMatch = true
-		result.Score = 100
-		result.MatchType = "exact_match"
+		// Логируем сомнительные совпадения для дальнейшего анализа
+		if result.MatchType == "possible_match" {
+			LogPossibleMatch(name1, name2, attrs, result)
+		}
+	}
+
+	result.HomoglyphsNormalized = normalized1 || normalized2
+	result.MixedScriptSuspected = mixed
+	return result
+}
+
+// matchReplaced сравнивает имена еще раз, заменив во втором имени слово word2 словом word1 из первого:
+// имя на другом языке ("Иосиф Бродский" - "Иосиф Brodsky" вместо "Joseph Brodsky") или полную форму
+// уменьшительной ("Саша Петров" - "Саша Петров" вместо "Александр Петров"). Замененная часть
+// оценивается долей share: оценка сравнения умножается на 1 - (1 - share) / N, где N - число частей
+// имени, и заменяет исходную, если она выше. Метрики, лучшие варианты и сопоставленные части
+// остаются от сравнения исходных имен, замена описывается в пояснении
+func (m *NameMatcher) matchReplaced(result MatchResult, kind, word1, word2 string, share float64, name1, name2 string, input1, input2 NameInput, attrs Attributes) MatchResult {
+	if result.Explanation == nil {
+		return result
+	}
+	localized := input2.replaceWord(word2, word1)
+	localizedName2, _ := translit.NormalizeHomoglyphs(localized.text())
+	if localizedName2 == name2 {
 		return result
 	}
 
+	cfg := &m.Config
+	// Имена, совпавшие после замены, точным совпадением не считаются и оцениваются метриками
+	alternative := m.scoreLegacy(name1, localizedName2, input1.declared(), localized.declared(), attrs)
+	parts := max(len(strings.Fields(name1)), len(strings.Fields(localizedName2)))
+	weight := 1 - (1-share)/float64(parts)
+	alternativeScore := int(math.Round(float64(alternative.Score) * weight))
+	if alternativeScore <= result.Score {
+		return result
+	}
+
+	result.Score = alternativeScore
+	if result.Score >= cfg.MatchThreshold {
+		result.MatchType = "match"
+	} else if result.Score >= cfg.PossibleMatchThreshold {
+		result.MatchType = "possible_match"
+	} else {
+		result.MatchType = "no_match"
+	}
+	result.ProcessingTimeMS += alternative.ProcessingTimeMS
+	result.Explanation.Substitution = &Substitution{
+		Kind:   kind,
+		Part1:  word1,
+		Part2:  word2,
+		Weight: weight,
+	}
+	return result
+}
+
+// matchLegacy сравнивает имена взвешенными метриками по перестановкам и транслитерациям с бонусами.
+// declared1 и declared2 - роли частей, заданные вызывающим (nil - роли определяет utils.ParseName)
+func (m *NameMatcher) matchLegacy(name1, name2 string, declared1, declared2 *utils.ParsedName, attrs Attributes) MatchResult {
+	// Проверяем точное совпадение
+	if namesEqual(name1, name2) {
+		return m.exactMatchResult(attrs, time.Now())
+	}
+
 	// Если имена не совпадают точно, выполняем расширенное сравнение
+	return m.scoreLegacy(name1, name2, declared1, declared2, attrs)
+}
+
+// scoreLegacy оценивает имена взвешенными метриками без проверки точного совпадения
+func (m *NameMatcher) scoreLegacy(name1, name2 string, declared1, declared2 *utils.ParsedName, attrs Attributes) MatchResult {
+	startTime := time.Now()
+	cfg := &m.Config
+
+	// Инициализируем результат
+	var result MatchResult
+
 	bestLevenshteinScore := 0.0
 	bestJaroWinklerScore := 0.0
 
+	// Приводим части имен к именительному падежу и при необходимости
+	// сводим мужскую и женскую формы фамилии к фамилии семьи
+	parsed1, parsed2 := parsedOrDeclared(name1, declared1), parsedOrDeclared(name2, declared2)
+	if cfg.MorphologyNormalization {
+		name1, parsed1 = normalizedName(name1, parsed1, utils.NormalizeMorphology(parsed1))
+		name2, parsed2 = normalizedName(name2, parsed2, utils.NormalizeMorphology(parsed2))
+	}
+	// Пол определяем до замены фамилий формой семьи
+	genderConflict := utils.GenderConflict(parsed1, parsed2)
+	if cfg.GenderPairedSurnamesAsFamily && utils.GenderPairedSurnames(parsed1, parsed2) {
+		name1, parsed1 = normalizedName(name1, parsed1, utils.FamilyForm(parsed1))
+		name2, parsed2 = normalizedName(name2, parsed2, utils.FamilyForm(parsed2))
+		result.SameFamily = true
+	}
+
 	// Разбиваем имена на части для учета возможных перестановок
 	name1Parts := strings.Fields(name1)
 	name2Parts := strings.Fields(name2)
@@ -39,52 +194,64 @@ func MatchNames(name1, name2 string, attrs Attributes, cfg *Config) MatchResult
 	name1Permutations := []string{name1}
 	name2Permutations := []string{name2}
 
-	// Если имя состоит из нескольких частей, генерируем перестановки
-	if len(name1Parts) > 1 {
-		for i := 0; i < len(name1Parts); i++ {
-			for j := i + 1; j < len(name1Parts); j++ {
-				// Переставляем части местами
-				permParts := make([]string, len(name1Parts))
-				copy(permParts, name1Parts)
-				permParts[i], permParts[j] = permParts[j], permParts[i]
-				name1Permutations = append(name1Permutations, strings.Join(permParts, " "))
+	// Если роли частей обоих имен определены, сравниваем фамилию с фамилией, имя с именем
+	// и отчество с отчеством вместо перестановок: "Иван Петров" не совпадает с "Петр Иванов"
+	if parsed1.Confident && parsed2.Confident {
+		name1Permutations, name2Permutations = roleOrderedVariants(parsed1, parsed2)
+	} else {
+		// Если имя состоит из нескольких частей, генерируем перестановки.
+		// Части имени с заданными ролями не переставляем
+		if len(name1Parts) > 1 && declared1 == nil {
+			for i := 0; i < len(name1Parts); i++ {
+				for j := i + 1; j < len(name1Parts); j++ {
+					// Переставляем части местами
+					permParts := make([]string, len(name1Parts))
+					copy(permParts, name1Parts)
+					permParts[i], permParts[j] = permParts[j], permParts[i]
+					name1Permutations = append(name1Permutations, strings.Join(permParts, " "))
+				}
 			}
 		}
-	}
 
-	if len(name2Parts) > 1 {
-		for i := 0; i < len(name2Parts); i++ {
-			for j := i + 1; j < len(name2Parts); j++ {
-				// Переставляем части местами
-				permParts := make([]string, len(name2Parts))
-				copy(permParts, name2Parts)
-				permParts[i], permParts[j] = permParts[j], permParts[i]
-				name2Permutations = append(name2Permutations, strings.Join(permParts, " "))
+		if len(name2Parts) > 1 && declared2 == nil {
+			for i := 0; i < len(name2Parts); i++ {
+				for j := i + 1; j < len(name2Parts); j++ {
+					// Переставляем части местами
+					permParts := make([]string, len(name2Parts))
+					copy(permParts, name2Parts)
+					permParts[i], permParts[j] = permParts[j], permParts[i]
+					name2Permutations = append(name2Permutations, strings.Join(permParts, " "))
+				}
 			}
 		}
 	}
 
 	// Объединяем перестановки с вариантами транслитерации
-	var allName1Variants []string
-	var allName2Variants []string
+	var allName1Variants []translit.Variant
+	var allName2Variants []translit.Variant
 
 	// Получаем все варианты транслитерации для всех перестановок имен
 	for _, perm := range name1Permutations {
-		variants := translit.GetAllTransliterations(perm)
-		allName1Variants = append(allName1Variants, variants...)
+		allName1Variants = append(allName1Variants, m.transliterations(perm)...)
 	}
 
 	for _, perm := range name2Permutations {
-		variants := translit.GetAllTransliterations(perm)
-		allName2Variants = append(allName2Variants, variants...)
+		allName2Variants = append(allName2Variants, m.transliterations(perm)...)
 	}
 
+	// Лучшая пара вариантов по сумме оценок Левенштейна и Джаро-Винклера
+	var bestVariant1, bestVariant2 translit.Variant
+	bestPairScore := -1.0
+
 	// Сравниваем каждую пару вариантов и выбираем наилучший результат
-	for _, variant1 := range allName1Variants {
-		for _, variant2 := range allName2Variants {
+	for _, v1 := range allName1Variants {
+		for _, v2 := range allName2Variants {
+			variant1, variant2 := v1.Text, v2.Text
+
 			// Вычисляем расстояние Левенштейна
-			currentLevenshteinDist := levenshteinDistance(strings.ToLower(variant1), strings.ToLower(variant2))
-			maxLen := math.Max(float64(len(variant1)), float64(len(variant2)))
+			// Длина нормализации считается в символах, как и само расстояние
+			currentLevenshteinDist := similarity.LevenshteinDistance(variant1, variant2)
+			maxLen := math.Max(float64(utf8.RuneCountInString(variant1)), float64(utf8.RuneCountInString(variant2)))
 			currentLevenshteinScore := 0.0
 			if maxLen > 0 {
 				currentLevenshteinScore = 1.0 - float64(currentLevenshteinDist)/maxLen
@@ -100,12 +267,34 @@ func MatchNames(name1, name2 string, attrs Attributes, cfg *Config) MatchResult
 			if currentJaroWinklerScore > bestJaroWinklerScore {
 				bestJaroWinklerScore = currentJaroWinklerScore
 			}
+			if pairScore := currentLevenshteinScore + currentJaroWinklerScore; pairScore > bestPairScore {
+				bestPairScore = pairScore
+				bestVariant1, bestVariant2 = v1, v2
+			}
 		}
 	}
 
-	// Вычисляем фонетические оценки
-	phoneticScore := similarity.SoundexSimilarity(name1, name2)
-	doubleMetaphoneScore := similarity.DoubleMetaphoneSimilarity(name1, name2)
+	// Вычисляем фонетические оценки. Имена, заданные по частям, сравниваются по частям
+	// с одинаковыми ролями: фамилия не совпадает с именем, даже если их коды равны
+	phonetic := m.wordPhonetic
+	if declared1 != nil && declared2 != nil {
+		phonetic = m.partwisePhonetic
+	}
+	phoneticScore, doubleMetaphoneScore := phonetic(name1, name2)
+
+	// Написание из паспорта ("IURII" для "Юрий") оценивается и по варианту того же стандарта:
+	// фонетические ключи исходного кириллического имени с ним не совпадают
+	if documentSpelling(bestVariant1, bestVariant2) || documentSpelling(bestVariant2, bestVariant1) {
+		variantPhonetic, variantMetaphone := phonetic(bestVariant1.Text, bestVariant2.Text)
+		phoneticScore = max(phoneticScore, variantPhonetic)
+		doubleMetaphoneScore = max(doubleMetaphoneScore, variantMetaphone)
+	}
+
+	// Сохраняем пару вариантов, давшую лучшее совпадение, и стандарты транслитерации
+	result.BestMatch1 = bestVariant1.Text
+	result.BestMatch2 = bestVariant2.Text
+	result.BestMatch1Standards = bestVariant1.Standards
+	result.BestMatch2Standards = bestVariant2.Standards
 
 	// Устанавливаем оценки в результат (округляем до двух знаков после запятой)
 	result.LevenshteinScore = math.Round(bestLevenshteinScore*100) / 100
@@ -113,12 +302,6 @@ func MatchNames(name1, name2 string, attrs Attributes, cfg *Config) MatchResult
 	result.PhoneticScore = math.Round(phoneticScore*100) / 100
 	result.DoubleMetaphoneScore = math.Round(doubleMetaphoneScore*100) / 100
 
-	// Устанавливаем конфигурацию, если не предоставлена
-	if cfg == nil {
-		defaultCfg := DefaultConfig()
-		cfg = &defaultCfg
-	}
-
 	// Вычисляем базовую оценку как взвешенное среднее всех оценок
 	avgScore := (result.LevenshteinScore*cfg.LevenshteinWeight +
 		result.JaroWinklerScore*cfg.JaroWinklerWeight +
@@ -129,57 +312,99 @@ func MatchNames(name1, name2 string, attrs Attributes, cfg *Config) MatchResult
 	baseScore := avgScore // Сохраняем базовую оценку
 	totalBonus := 0.0     // Суммарный бонус
 
+	// Сработавшие бонусы сохраняем для пояснения к оценке
+	var bonuses []Bonus
+	addBonus := func(name string, value float64) {
+		bonuses = append(bonuses, Bonus{Name: name, Value: value})
+		totalBonus += value
+	}
+
 	// Бонус 1: Транслитерация между алфавитами (до 12%)
-	if translit.IsCyrillic(name1) != translit.IsCyrillic(name2) {
+	if !translit.SameScripts(name1, name2) {
 		// Для транслитерации даём бонус (больше для хороших фонетических совпадений)
 		if result.PhoneticScore > 0.8 {
-			totalBonus += 0.12 // 12% бонус для хороших фонетических совпадений
+			addBonus(BonusTransliteration, 0.12) // 12% бонус для хороших фонетических совпадений
 		} else {
-			totalBonus += 0.08 // 8% бонус для обычных случаев
+			addBonus(BonusTransliteration, 0.08) // 8% бонус для обычных случаев
 		}
 	}
 
 	// Бонус 2: Перестановки частей ФИО (до 12%)
 	// Проверяем, является ли одно имя перестановкой другого
 	if isNamePartsPermutation(name1, name2) {
-		totalBonus += 0.12 // 12% бонус
+		addBonus(BonusPermutation, 0.12) // 12% бонус
 	}
 
 	// Бонус 3: Обработка инициалов (до 15%)
 	if hasInitialsAtStart(name1, name2) {
 		if strings.Contains(name1, ".") || strings.Contains(name2, ".") {
-			totalBonus += 0.10 // 10% бонус за инициалы с точками
+			addBonus(BonusInitials, 0.10) // 10% бонус за инициалы с точками
 		} else {
-			totalBonus += 0.15 // 15% бонус за инициалы без точек (полный инициал)
+			addBonus(BonusInitials, 0.15) // 15% бонус за инициалы без точек (полный инициал)
 		}
 	}
 
 	// Бонус 4: Обработка дефисных имен (до 8%)
 	if hasHyphenatedName(name1) || hasHyphenatedName(name2) {
-		totalBonus += 0.08 // 8% бонус
+		addBonus(BonusHyphen, 0.08) // 8% бонус
 	}
 
 	// Бонус 5: Обработка уменьшительных/альтернативных форм имен (до 12%)
 	if isNameFormVariation(name1, name2) {
-		totalBonus += 0.12 // 12% бонус
+		addBonus(BonusNameForm, 0.12) // 12% бонус
+	}
+
+	// Бонус 6: Соответствие имени на другом языке ("Пётр" - "Pierre", до 12%)
+	_, _, result.CognateScore = cognatePair(name1, name2)
+	if result.CognateScore > 0 && cfg.CognateBonus > 0 {
+		addBonus(BonusCognate, math.Round(cfg.CognateBonus*result.CognateScore*10000)/10000)
+	}
+
+	explanation := &Explanation{
+		BaseScore: math.Round(baseScore*10000) / 10000,
+		Bonuses:   bonuses,
 	}
 
 	// Применяем совокупный бонус к базовой оценке, но не больше 30%
 	if totalBonus > 0.3 {
 		totalBonus = 0.3
+		explanation.BonusCapped = true
 	}
+	explanation.TotalBonus = math.Round(totalBonus*10000) / 10000
 
 	// Применяем бонус к базовой оценке
 	avgScore = baseScore * (1.0 + totalBonus)
 
+	// Учитываем дополнительные атрибуты, если они переданы
+	avgScore, explanation.AttributesApplied = m.applyAttributes(avgScore, attrs, &result)
+
+	// Снижаем оценку, если имена указывают на разный пол
+	if genderConflict {
+		result.GenderConflict = true
+		if cfg.GenderConflictPenalty > 0 {
+			avgScore *= 1 - cfg.GenderConflictPenalty
+			explanation.GenderPenalty = cfg.GenderConflictPenalty
+		}
+	}
+
 	// Ограничиваем максимальное значение до 0.99 (чтобы оставить 100% только для точных совпадений)
 	if avgScore > 0.99 {
 		avgScore = 0.99
+		explanation.ScoreClamped = true
 	}
 
+	explanation.AlignedParts = m.alignParts(parsed1, parsed2)
+	result.Explanation = explanation
+
 	// Переводим в шкалу 0-100
 	result.Score = int(math.Round(avgScore * 100))
 
+	// Члены одной семьи с мужской и женской формами фамилии - не один человек.
+	// Имена разного пола тоже не считаются совпадением, даже если бонусы поглотили снижение оценки
+	if (result.SameFamily || result.GenderConflict && cfg.GenderConflictPenalty > 0) && result.Score >= cfg.MatchThreshold {
+		result.Score = cfg.MatchThreshold - 1
+	}
+
 	// Определяем тип совпадения на основе оценки
 	if result.Score >= cfg.MatchThreshold {
 		result.MatchType = "match"
@@ -189,14 +414,187 @@ func MatchNames(name1, name2 string, attrs Attributes, cfg *Config) MatchResult
 		result.MatchType = "no_match"
 	}
 
-	// Логируем сомнительные совпадения для дальнейшего анализа
-	if result.MatchType == "possible_match" {
-		LogPossibleMatch(name1, name2, attrs, result)
+	result.ProcessingTimeMS = time.Since(startTime).Milliseconds()
+
+	return result
+}
+
+// namesEqual проверяет точное совпадение имен: имена равны после предобработки utils.PreprocessName
+// без учета регистра, дефисов, апострофов и лишних пробелов ("Петрова-Сидорова Анна" - "петрова сидорова анна").
+// Оба конвейера сравнения определяют точное совпадение этой функцией
+func namesEqual(name1, name2 string) bool {
+	processed1 := utils.PreprocessName(name1)
+	return processed1 != "" && processed1 == utils.PreprocessName(name2)
+}
+
+// exactMatchResult формирует результат точного совпадения имен.
+// Оценка атрибутов сообщается в результате, но не меняет оценку 100
+func (m *NameMatcher) exactMatchResult(attrs Attributes, startTime time.Time) MatchResult {
+	result := MatchResult{
+		ExactMatch: true,
+		Score:      100,
+		MatchType:  "exact_match",
 	}
+	if attrsScore, ok := m.attributesScore(attrs); ok {
+		result.AdditionalAttributesScore = math.Round(attrsScore*100) / 100
+	}
+	result.ProcessingTimeMS = time.Since(startTime).Milliseconds()
 
 	return result
 }
 
+// phoneticSimilarity вычисляет фонетическую оценку кодировщиками из конфигурации.
+// Неизвестный кодировщик заменяется на Soundex
+func (m *NameMatcher) phoneticSimilarity(name1, name2 string) float64 {
+	if len(m.Config.PhoneticEncoders) > 0 {
+		return similarity.WeightedEncoderSimilarity(name1, name2, m.Config.PhoneticEncoders)
+	}
+
+	encoder, ok := similarity.LookupEncoder(m.Config.PhoneticEncoder)
+	if !ok {
+		return similarity.SoundexSimilarity(name1, name2)
+	}
+	return similarity.EncoderSimilarity(encoder, name1, name2)
+}
+
+// transliterations возвращает варианты транслитерации имени,
+// используя кэш транслитераций экземпляра, если он инициализирован
+func (m *NameMatcher) transliterations(name string) []translit.Variant {
+	if m.translitVariants == nil {
+		return m.transliterate(name)
+	}
+
+	m.mutex.RLock()
+	variants, ok := m.translitVariants[name]
+	m.mutex.RUnlock()
+	if ok {
+		return variants
+	}
+
+	variants = m.transliterate(name)
+
+	m.mutex.Lock()
+	// Не даем кэшу транслитераций расти бесконечно
+	if len(m.translitVariants) >= m.Config.MaxCacheSize {
+		m.translitVariants = make(map[string][]translit.Variant)
+	}
+	m.translitVariants[name] = variants
+	m.mutex.Unlock()
+
+	return variants
+}
+
+// transliterate строит варианты транслитерации имени по стандартам из конфигурации
+// и по стандарту языка имени (LanguageHint или определенного по буквам).
+// При отключенной транслитерации используется только исходное написание
+func (m *NameMatcher) transliterate(name string) []translit.Variant {
+	if !m.Config.EnableTransliteration {
+		return translit.GetTransliterationVariants(name, nil)
+	}
+
+	variants := translit.GetTransliterationVariants(name, m.Config.TransliterationStandards)
+
+	language := m.Config.LanguageHint
+	if language == "" {
+		language = translit.DetectLanguage(name)
+	}
+
+	return translit.AddLanguageVariants(variants, name, language)
+}
+
+// wordPhonetic возвращает фонетическую оценку и оценку Double Metaphone по словам имен
+// независимо от их порядка
+func (m *NameMatcher) wordPhonetic(name1, name2 string) (float64, float64) {
+	return m.phoneticSimilarity(name1, name2), similarity.DoubleMetaphoneSimilarity(metaphoneText(name1), metaphoneText(name2))
+}
+
+// metaphoneText возвращает текст для Double Metaphone: алгоритм кодирует только латиницу,
+// поэтому кириллица кодируется по транслитерации ГОСТ
+func metaphoneText(name string) string {
+	if translit.IsCyrillic(name) {
+		return translit.TranslitGOST(name)
+	}
+	return name
+}
+
+// partwisePhonetic возвращает фонетическую оценку и оценку Double Metaphone, сравнивая части
+// имен на одинаковых позициях; части без пары дают нулевую оценку
+func (m *NameMatcher) partwisePhonetic(name1, name2 string) (float64, float64) {
+	parts1, parts2 := strings.Fields(name1), strings.Fields(name2)
+	total := max(len(parts1), len(parts2))
+	if total == 0 {
+		return 0, 0
+	}
+
+	phoneticScore, doubleMetaphoneScore := 0.0, 0.0
+	for i := 0; i < min(len(parts1), len(parts2)); i++ {
+		phoneticScore += m.phoneticSimilarity(parts1[i], parts2[i])
+		doubleMetaphoneScore += similarity.DoubleMetaphoneSimilarity(metaphoneText(parts1[i]), metaphoneText(parts2[i]))
+	}
+	return phoneticScore / float64(total), doubleMetaphoneScore / float64(total)
+}
+
+// documentSpelling проверяет, что вариант транслитерации получен по паспортному стандарту,
+// а другое имя сравнивается в исходном написании
+func documentSpelling(variant, other translit.Variant) bool {
+	original, document := false, false
+	for _, standard := range other.Standards {
+		original = original || standard == translit.StandardOriginal
+	}
+	for _, standard := range variant.Standards {
+		document = document || translit.IsDocumentStandard(standard)
+	}
+	return original && document
+}
+
+// normalizedName возвращает имя, собранное из нормализованных частей, если они изменились
+func normalizedName(name string, parsed, normalized utils.ParsedName) (string, utils.ParsedName) {
+	if len(parsed.Parts) == len(normalized.Parts) {
+		same := true
+		for i := range parsed.Parts {
+			same = same && parsed.Parts[i] == normalized.Parts[i]
+		}
+		if same {
+			return name, parsed
+		}
+	}
+	return normalized.Text(), normalized
+}
+
+// parsedOrDeclared возвращает роли частей, заданные вызывающим, или разбор имени utils.ParseName
+func parsedOrDeclared(name string, declared *utils.ParsedName) utils.ParsedName {
+	if declared != nil {
+		return *declared
+	}
+	return utils.ParseName(name)
+}
+
+// roleOrderedVariants возвращает варианты имен с частями в порядке ролей (см. utils.ParsedName.Ordered).
+// Полное имя сравнивается и без отчества, если у другого имени отчества нет, а имя указано полностью
+func roleOrderedVariants(parsed1, parsed2 utils.ParsedName) ([]string, []string) {
+	variants1 := []string{strings.Join(parsed1.Ordered(), " ")}
+	variants2 := []string{strings.Join(parsed2.Ordered(), " ")}
+
+	if withoutPatronymic, ok := dropPatronymic(parsed1, parsed2); ok {
+		variants1 = append(variants1, withoutPatronymic)
+	}
+	if withoutPatronymic, ok := dropPatronymic(parsed2, parsed1); ok {
+		variants2 = append(variants2, withoutPatronymic)
+	}
+
+	return variants1, variants2
+}
+
+// dropPatronymic возвращает первое имя без отчества, если у второго имени отчества нет,
+// а имя указано полностью ("Петров Сергей" - "Петров Сергей Иванович")
+func dropPatronymic(parsed, other utils.ParsedName) (string, bool) {
+	if parsed.Patronymic == "" || other.Patronymic != "" || other.GivenName == "" || len(other.Initials) > 0 {
+		return "", false
+	}
+	parsed.Patronymic = ""
+	return strings.Join(parsed.Ordered(), " "), true
+}
+
 // hasInitialsAtStart проверяет, начинается ли одно из имен с инициалов, а другое с полных имен
 func hasInitialsAtStart(name1, name2 string) bool {
 
@@ -321,6 +719,12 @@ func AddAttribute(attrs Attributes, name string, match bool) {
 	attrs[name] = Attribute{Match: match}
 }
 
+// AddAttributeValues добавляет атрибут со значениями обеих сторон,
+// которые сравниваются по правилу из Config.AttributeRules
+func AddAttributeValues(attrs Attributes, name, value1, value2 string) {
+	attrs[name] = Attribute{Value1: value1, Value2: value2}
+}
+
 // isNamePartsPermutation проверяет, является ли одно имя перестановкой другого
 func isNamePartsPermutation(name1, name2 string) bool {
 	// Разбиваем имена на части
@@ -370,25 +774,6 @@ func hasHyphenatedName(name string) bool {
 
 // isNameFormVariation проверяет, является ли одно имя вариацией другого (например, "Александр" и "Саша")
 func isNameFormVariation(name1, name2 string) bool {
-	// Словарь соответствий имен и их уменьшительных/альтернативных форм
-	nameVariations := map[string][]string{
-		"александр": {"саша", "шура", "алекс", "саня"},
-		"алексей":   {"леша", "леха", "алеша", "alex", "alexey", "aleksei"},
-		"анастасия": {"настя", "ася", "стася"},
-		"анна":      {"аня", "анюта", "анечка"},
-		"дмитрий":   {"дима", "димуля", "митя", "dmitry", "dmitri"},
-		"екатерина": {"катя", "катерина", "катюша"},
-		"елена":     {"лена", "леночка", "helen", "helena"},
-		"иван":      {"ваня", "иванушка", "ivan"},
-		"мария":     {"маша", "машенька", "mary", "maria"},
-		"михаил":    {"миша", "michael", "mikhail"},
-		"ольга":     {"оля", "оленька", "olga", "olya"},
-		"сергей":    {"серега", "сергеич", "sergey", "sergei"},
-		"татьяна":   {"таня", "танюша", "tatiana", "tanya"},
-		"юрий":      {"юра", "yuri", "yury", "jurij", "juri"},
-		// Можно добавить больше соответствий
-	}
-
 	// Преобразуем имена к нижнему регистру для сравнения
 	lowerName1 := strings.ToLower(name1)
 	lowerName2 := strings.ToLower(name2)
@@ -405,26 +790,47 @@ func isNameFormVariation(name1, name2 string) bool {
 				return true
 			}
 
-			// Затем проверяем, является ли одно слово вариацией другого
-			if variations, ok := nameVariations[word1]; ok {
-				for _, variation := range variations {
-					if variation == word2 {
-						return true
-					}
-				}
+			// Затем проверяем, является ли одно слово формой того же имени по словарю
+			if utils.Dictionary().Related(word1, word2) {
+				return true
 			}
+		}
+	}
 
-			if variations, ok := nameVariations[word2]; ok {
-				for _, variation := range variations {
-					if variation == word1 {
-						return true
-					}
-				}
+	return false
+}
+
+// cognatePair возвращает пару слов двух имен с наибольшей оценкой межъязыкового соответствия
+// (см. utils.NameDictionary.CognateScore). Формы одного имени по словарю не учитываются:
+// для них начисляется бонус за уменьшительную форму
+func cognatePair(name1, name2 string) (string, string, float64) {
+	var best1, best2 string
+	best := 0.0
+	for _, word1 := range strings.Fields(name1) {
+		for _, word2 := range strings.Fields(name2) {
+			if utils.Dictionary().Related(word1, word2) {
+				continue
+			}
+			if score := utils.Dictionary().CognateScore(word1, word2); score > best {
+				best1, best2, best = word1, word2, score
 			}
 		}
 	}
+	return best1, best2, best
+}
 
-	return false
+// nicknamePair возвращает пару разных слов двух имен на одном алфавите, которые по словарю
+// являются формами одного имени ("Саша" - "Александр"). Формы на разных алфавитах ("Иван" - "John")
+// сюда не входят: соответствие имен на разных языках оценивается через cognatePair
+func nicknamePair(name1, name2 string) (string, string, bool) {
+	for _, word1 := range strings.Fields(name1) {
+		for _, word2 := range strings.Fields(name2) {
+			if !strings.EqualFold(word1, word2) && translit.SameScripts(word1, word2) && utils.Dictionary().Related(word1, word2) {
+				return word1, word2, true
+			}
+		}
+	}
+	return "", "", false
 }
 
 // PrintMatchResult выводит результат сравнения в консоль
@@ -434,9 +840,25 @@ func PrintMatchResult(result MatchResult) {
 	fmt.Printf("  Оценка: %d\n", result.Score)
 	fmt.Printf("  Тип совпадения: %s\n", result.MatchType)
 
+	if result.HomoglyphsNormalized {
+		fmt.Printf("  Заменены буквы-двойники другого алфавита\n")
+	}
+	if result.MixedScriptSuspected {
+		fmt.Printf("  Смешение алфавитов в имени (возможная подмена символов)\n")
+	}
+	if result.SameFamily {
+		fmt.Printf("  Мужская и женская формы одной фамилии\n")
+	}
+	if result.GenderConflict {
+		fmt.Printf("  Имена указывают на разный пол\n")
+	}
+	if result.CognateScore > 0 {
+		fmt.Printf("  Соответствие имени на другом языке: %.2f\n", result.CognateScore)
+	}
+
 	if result.BestMatch1 != "" && result.BestMatch2 != "" {
-		fmt.Printf("  Лучшее совпадение 1: %s\n", result.BestMatch1)
-		fmt.Printf("  Лучшее совпадение 2: %s\n", result.BestMatch2)
+		fmt.Printf("  Лучшее совпадение 1: %s %v\n", result.BestMatch1, result.BestMatch1Standards)
+		fmt.Printf("  Лучшее совпадение 2: %s %v\n", result.BestMatch2, result.BestMatch2Standards)
 	}
 
 	fmt.Printf("  Оценки алгоритмов:\n")
@@ -450,6 +872,23 @@ func PrintMatchResult(result MatchResult) {
 		fmt.Printf("    Дополнительные атрибуты: %.4f\n", result.AdditionalAttributesScore)
 	}
 
+	if e := result.Explanation; e != nil {
+		fmt.Printf("  Пояснение:\n")
+		fmt.Printf("    Базовая оценка: %.4f\n", e.BaseScore)
+		for _, bonus := range e.Bonuses {
+			fmt.Printf("    Бонус %s: +%.2f\n", bonus.Name, bonus.Value)
+		}
+		if e.BonusCapped {
+			fmt.Printf("    Суммарный бонус ограничен: %.2f\n", e.TotalBonus)
+		}
+		if e.ScoreClamped {
+			fmt.Printf("    Оценка ограничена значением 0.99\n")
+		}
+		for _, part := range e.AlignedParts {
+			fmt.Printf("    %s: %q <-> %q (%.2f)\n", part.Role, part.Part1, part.Part2, part.Score)
+		}
+	}
+
 	fmt.Printf("  Время обработки: %d мс\n", result.ProcessingTimeMS)
 
 	if result.FromCache {
@@ -0,0 +1,56 @@
+package matcher
+
+import (
+	"strings"
+
+	"github.com/x0rium/compareNames/matcher/mrz"
+	"github.com/x0rium/compareNames/matcher/translit"
+	"github.com/x0rium/compareNames/matcher/utils"
+)
+
+// MatchNameToMRZ сравнивает полное имя с именем из машиночитаемой зоны документа.
+// Имя на кириллице сравнивается в транслитерации ICAO Doc 9303, по которой заполняется MRZ;
+// если поле имени MRZ усечено, имя усекается так же.
+// Для атрибутов birth_date, gender, document_number и citizenship, у которых задано только
+// значение Value1, значение Value2 берется из MRZ (пол - "M" или "F", дата - ГГГГ-ММ-ДД).
+// Возвращает ошибку, если MRZ не удалось разобрать или не сошлись контрольные цифры
+func MatchNameToMRZ(fullName string, mrzLines []string, attrs Attributes, cfg *Config) (MatchResult, error) {
+	doc, err := mrz.Parse(mrzLines)
+	if err != nil {
+		return MatchResult{}, err
+	}
+
+	name := fullName
+	if doc.NameTruncated {
+		// Усеченное поле имени сравнивается с полем той же длины, заполненным по полному имени
+		parsed := utils.ParseName(fullName)
+		given := strings.TrimSpace(parsed.GivenName + " " + parsed.Patronymic)
+		field := translit.MRZNameField(parsed.Surname, given, doc.NameFieldLength())
+		surname, givenNames, _ := translit.ParseMRZNameField(field)
+		name = strings.TrimSpace(surname + " " + givenNames)
+	} else if translit.IsCyrillic(name) {
+		name = translit.TranslitICAO(name)
+	}
+
+	return MatchNames(name, doc.FullName(), mrzAttributes(doc, attrs), cfg), nil
+}
+
+// mrzAttributes дополняет атрибуты значениями из MRZ, не изменяя исходную карту
+func mrzAttributes(doc *mrz.Document, attrs Attributes) Attributes {
+	values := map[string]string{
+		"birth_date":      doc.BirthDate.Format("2006-01-02"),
+		"gender":          doc.Sex,
+		"document_number": doc.DocumentNumber,
+		"citizenship":     doc.Nationality,
+	}
+
+	result := make(Attributes, len(attrs))
+	for name, attr := range attrs {
+		if value := values[name]; value != "" && attr.Value1 != "" && attr.Value2 == "" {
+			attr.Value2 = value
+		}
+		result[name] = attr
+	}
+
+	return result
+}
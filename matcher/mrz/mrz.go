@@ -0,0 +1,219 @@
+package mrz
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/x0rium/compareNames/matcher/translit"
+)
+
+// Форматы машиночитаемой зоны (ICAO Doc 9303)
+const (
+	FormatTD1 = "TD1" // ID-карты: 3 строки по 30 символов
+	FormatTD3 = "TD3" // Паспорта: 2 строки по 44 символа
+)
+
+// Ошибки разбора MRZ
+var (
+	ErrInvalidFormat = errors.New("mrz: unsupported format")
+	ErrCheckDigit    = errors.New("mrz: check digit mismatch")
+)
+
+// Document данные документа из машиночитаемой зоны
+type Document struct {
+	Format         string    `json:"format"`
+	DocumentCode   string    `json:"document_code"`
+	IssuingState   string    `json:"issuing_state"`
+	Surname        string    `json:"surname"`
+	GivenNames     string    `json:"given_names"`
+	NameTruncated  bool      `json:"name_truncated,omitempty"` // Поле имени заполнено полностью и могло быть усечено
+	DocumentNumber string    `json:"document_number"`
+	Nationality    string    `json:"nationality"`
+	BirthDate      time.Time `json:"birth_date"`
+	Sex            string    `json:"sex,omitempty"` // "M", "F" или пусто, если не указан
+	ExpiryDate     time.Time `json:"expiry_date"`
+	OptionalData   string    `json:"optional_data,omitempty"`
+}
+
+// FullName возвращает фамилию и имена через пробел
+func (d *Document) FullName() string {
+	return strings.TrimSpace(d.Surname + " " + d.GivenNames)
+}
+
+// NameFieldLength возвращает длину поля имени в формате документа
+func (d *Document) NameFieldLength() int {
+	if d.Format == FormatTD1 {
+		return 30
+	}
+	return translit.MRZNameLength
+}
+
+// Parse разбирает строки MRZ формата TD1 или TD3 и проверяет контрольные цифры
+func Parse(lines []string) (*Document, error) {
+	normalized := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = strings.ToUpper(strings.TrimSpace(line)); line != "" {
+			normalized = append(normalized, line)
+		}
+	}
+
+	switch {
+	case len(normalized) == 2 && len(normalized[0]) == 44 && len(normalized[1]) == 44:
+		return parseTD3(normalized[0], normalized[1])
+	case len(normalized) == 3 && len(normalized[0]) == 30 && len(normalized[1]) == 30 && len(normalized[2]) == 30:
+		return parseTD1(normalized[0], normalized[1], normalized[2])
+	default:
+		return nil, ErrInvalidFormat
+	}
+}
+
+// parseTD3 разбирает MRZ паспорта (TD3)
+func parseTD3(line1, line2 string) (*Document, error) {
+	doc := &Document{
+		Format:         FormatTD3,
+		DocumentCode:   strings.TrimRight(line1[0:2], "<"),
+		IssuingState:   strings.TrimRight(line1[2:5], "<"),
+		DocumentNumber: strings.TrimRight(line2[0:9], "<"),
+		Nationality:    strings.TrimRight(line2[10:13], "<"),
+		Sex:            parseSex(line2[20]),
+		OptionalData:   strings.TrimRight(line2[28:42], "<"),
+	}
+	doc.Surname, doc.GivenNames, doc.NameTruncated = translit.ParseMRZNameField(line1[5:44])
+
+	checks := []checkField{
+		{"document number", line2[0:9], line2[9]},
+		{"birth date", line2[13:19], line2[19]},
+		{"expiry date", line2[21:27], line2[27]},
+		{"composite", line2[0:10] + line2[13:20] + line2[21:43], line2[43]},
+	}
+	// Контрольная цифра дополнительных данных может быть заполнителем, если данных нет
+	if line2[42] != '<' || strings.Trim(line2[28:42], "<") != "" {
+		checks = append(checks, checkField{"optional data", line2[28:42], line2[42]})
+	}
+	if err := verifyAll(checks); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if doc.BirthDate, err = parseDate(line2[13:19], false); err != nil {
+		return nil, err
+	}
+	if doc.ExpiryDate, err = parseDate(line2[21:27], true); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// parseTD1 разбирает MRZ ID-карты (TD1)
+func parseTD1(line1, line2, line3 string) (*Document, error) {
+	doc := &Document{
+		Format:       FormatTD1,
+		DocumentCode: strings.TrimRight(line1[0:2], "<"),
+		IssuingState: strings.TrimRight(line1[2:5], "<"),
+		Sex:          parseSex(line2[7]),
+		Nationality:  strings.TrimRight(line2[15:18], "<"),
+		OptionalData: strings.TrimRight(line1[15:30], "<") + strings.TrimRight(line2[18:29], "<"),
+	}
+	doc.Surname, doc.GivenNames, doc.NameTruncated = translit.ParseMRZNameField(line3)
+
+	// Номер длиннее 9 символов продолжается в дополнительных данных, а вместо
+	// контрольной цифры стоит заполнитель; контрольная цифра идет после продолжения номера
+	number, digit := line1[5:14], line1[14]
+	if digit == '<' {
+		rest := strings.SplitN(line1[15:30], "<", 2)[0]
+		if rest == "" {
+			return nil, fmt.Errorf("%w: document number", ErrCheckDigit)
+		}
+		number, digit = number+rest[:len(rest)-1], rest[len(rest)-1]
+		doc.OptionalData = strings.TrimPrefix(doc.OptionalData, rest)
+	}
+	doc.DocumentNumber = strings.TrimRight(number, "<")
+
+	checks := []checkField{
+		{"document number", number, digit},
+		{"birth date", line2[0:6], line2[6]},
+		{"expiry date", line2[8:14], line2[14]},
+		{"composite", line1[5:30] + line2[0:7] + line2[8:15] + line2[18:29], line2[29]},
+	}
+	if err := verifyAll(checks); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if doc.BirthDate, err = parseDate(line2[0:6], false); err != nil {
+		return nil, err
+	}
+	if doc.ExpiryDate, err = parseDate(line2[8:14], true); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// CheckDigit вычисляет контрольную цифру поля MRZ по ICAO Doc 9303 (веса 7, 3, 1)
+func CheckDigit(value string) int {
+	weights := [3]int{7, 3, 1}
+	sum := 0
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		var v int
+		switch {
+		case c >= '0' && c <= '9':
+			v = int(c - '0')
+		case c >= 'A' && c <= 'Z':
+			v = int(c-'A') + 10
+		default: // Заполнитель '<'
+			v = 0
+		}
+		sum += v * weights[i%3]
+	}
+	return sum % 10
+}
+
+// checkField поле MRZ с контрольной цифрой
+type checkField struct {
+	field string
+	value string
+	digit byte
+}
+
+// verifyAll сверяет контрольные цифры полей
+func verifyAll(checks []checkField) error {
+	for _, c := range checks {
+		if c.digit < '0' || c.digit > '9' || CheckDigit(c.value) != int(c.digit-'0') {
+			return fmt.Errorf("%w: %s", ErrCheckDigit, c.field)
+		}
+	}
+	return nil
+}
+
+// parseDate разбирает дату YYMMDD. Век выбирается так, чтобы дата рождения
+// не оказалась в будущем, а срок действия - не более чем на 50 лет в прошлом
+func parseDate(value string, expiry bool) (time.Time, error) {
+	date, err := time.Parse("060102", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("mrz: invalid date %q", value)
+	}
+
+	// time.Parse относит годы 69-99 к XX веку, а 00-68 к XXI
+	now := time.Now()
+	if !expiry && date.After(now) {
+		date = date.AddDate(-100, 0, 0)
+	}
+	if expiry && date.Before(now.AddDate(-50, 0, 0)) {
+		date = date.AddDate(100, 0, 0)
+	}
+
+	return date, nil
+}
+
+// parseSex разбирает поле пола
+func parseSex(c byte) string {
+	if c == 'M' || c == 'F' {
+		return string(c)
+	}
+	return ""
+}
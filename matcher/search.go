@@ -0,0 +1,89 @@
+package matcher
+
+import (
+	"container/heap"
+	"context"
+	"sort"
+	"sync"
+)
+
+// Candidate кандидат из списка вместе с результатом его сравнения с искомым именем
+type Candidate struct {
+	Index  int         `json:"index"`
+	Name   string      `json:"name"`
+	Result MatchResult `json:"result"`
+}
+
+// FindBestMatches возвращает до k кандидатов, наиболее похожих на query,
+// упорядоченных по убыванию MatchResult.Score (при k <= 0 - всех кандидатов)
+func FindBestMatches(query string, candidates []string, k int, cfg *Config) []Candidate {
+	matches, _ := newNameMatcher(cfg).FindBestMatches(context.Background(), query, candidates, k, 0)
+	return matches
+}
+
+// FindBestMatches возвращает до k кандидатов с оценкой не ниже minScore, наиболее похожих
+// на query, упорядоченных по убыванию оценки. Кандидаты сравниваются конкурентно,
+// в памяти хранятся только k лучших. Результаты не кэшируются, чтобы разовые пары
+// поиска не вытесняли из кэша повторяющиеся сравнения
+func (m *NameMatcher) FindBestMatches(ctx context.Context, query string, candidates []string, k int, minScore int) ([]Candidate, error) {
+	best := &candidateHeap{}
+	var mutex sync.Mutex
+
+	runParallel(len(candidates), 0, func(i int) {
+		if ctx.Err() != nil || candidates[i] == "" {
+			return
+		}
+
+		result := m.match(query, candidates[i], nil)
+		if result.Score < minScore {
+			return
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		candidate := Candidate{Index: i, Name: candidates[i], Result: result}
+		if k <= 0 || best.Len() < k {
+			heap.Push(best, candidate)
+		} else if candidateLess((*best)[0], candidate) {
+			// Новый кандидат лучше худшего из отобранных
+			(*best)[0] = candidate
+			heap.Fix(best, 0)
+		}
+	})
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	matches := []Candidate(*best)
+	sort.Slice(matches, func(i, j int) bool {
+		return candidateLess(matches[j], matches[i])
+	})
+
+	return matches, nil
+}
+
+// candidateLess сравнивает кандидатов по качеству: a хуже b, если у него ниже оценка,
+// а при равных оценках - больше индекс в исходном списке
+func candidateLess(a, b Candidate) bool {
+	if a.Result.Score != b.Result.Score {
+		return a.Result.Score < b.Result.Score
+	}
+	return a.Index > b.Index
+}
+
+// candidateHeap min-куча кандидатов: в вершине находится худший из отобранных
+type candidateHeap []Candidate
+
+func (h candidateHeap) Len() int            { return len(h) }
+func (h candidateHeap) Less(i, j int) bool  { return candidateLess(h[i], h[j]) }
+func (h candidateHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *candidateHeap) Push(x interface{}) { *h = append(*h, x.(Candidate)) }
+func (h *candidateHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
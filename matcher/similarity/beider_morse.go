@@ -0,0 +1,113 @@
+package similarity
+
+import (
+	"sort"
+	"strings"
+)
+
+// bmRules упрощенные правила Beider-Morse: буквосочетание и его возможные произношения
+// в немецком, польском, русском, идише и английском написании. Фонетический алфавит:
+// S - "ш", Z - "ж", x - "х"
+var bmRules = map[string][]string{
+	"szcz": {"StS"}, "tsch": {"tS"}, "schtsch": {"StS"}, "shch": {"StS"},
+	"sch": {"S"}, "tch": {"tS"}, "tsh": {"tS"}, "dzh": {"dZ"}, "chs": {"ks"},
+	"ch": {"tS", "x"}, "cz": {"tS"}, "cs": {"tS"}, "sh": {"S"}, "sz": {"S", "s"},
+	"zh": {"Z"}, "rz": {"Z", "rz", "rts"}, "dz": {"dz"}, "kh": {"x"},
+	"ph": {"f"}, "th": {"t"}, "ts": {"ts"}, "tz": {"ts"}, "ck": {"k"},
+	"ou": {"u"}, "oo": {"u"}, "ie": {"i"}, "ae": {"e"}, "oe": {"e"}, "ue": {"u"},
+	"q": {"k"}, "x": {"ks"}, "w": {"v"}, "j": {"j", "dZ"}, "y": {"i"}, "z": {"z", "ts"},
+}
+
+// bmApprox сведение звуков для приблизительного сравнения: звонкие согласные
+// оглушаются, гласные сводятся к трем классам, h не учитывается
+var bmApprox = map[rune]string{
+	'b': "p", 'd': "t", 'g': "k", 'v': "f", 'z': "s", 'Z': "S",
+	'a': "o", 'o': "o", 'u': "u", 'e': "i", 'i': "i", 'j': "i",
+	'h': "",
+}
+
+// bmMaxPattern длина самого длинного буквосочетания в bmRules
+const bmMaxPattern = 7
+
+// bmMaxVariants ограничение числа фонетических вариантов одного слова
+const bmMaxVariants = 32
+
+// BeiderMorse реализует упрощенный вариант Beider-Morse Phonetic Matching для еврейских
+// и восточноевропейских фамилий. Слово переводится во все возможные фонетические
+// записи с учетом неоднозначных буквосочетаний ("ch", "sz", "rz", "z"), которые затем
+// сводятся к приблизительной форме. Например, "Schwarzman" и "Shvartsman" имеют
+// общий код. Кириллица предварительно транслитерируется по ГОСТ
+func BeiderMorse(word string) []string {
+	word = strings.ToLower(asciiWord(word))
+	if word == "" {
+		return nil
+	}
+
+	variants := []string{""}
+	for i := 0; i < len(word); {
+		pattern, sounds := bmMatch(word, i)
+		next := make([]string, 0, len(variants)*len(sounds))
+		for _, variant := range variants {
+			for _, sound := range sounds {
+				if len(next) < bmMaxVariants {
+					next = append(next, variant+sound)
+				}
+			}
+		}
+		variants = next
+		i += len(pattern)
+	}
+
+	seen := make(map[string]bool, len(variants))
+	result := make([]string, 0, len(variants))
+	for _, variant := range variants {
+		code := bmApproximate(variant)
+		if code != "" && !seen[code] {
+			seen[code] = true
+			result = append(result, code)
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// bmMatch возвращает самое длинное буквосочетание с позиции i и его произношения.
+// Буква c перед e, i, y читается как "ц", в остальных случаях как "к"
+func bmMatch(word string, i int) (string, []string) {
+	for n := bmMaxPattern; n > 0; n-- {
+		if i+n > len(word) {
+			continue
+		}
+		if sounds, ok := bmRules[word[i:i+n]]; ok {
+			return word[i : i+n], sounds
+		}
+	}
+
+	if word[i] == 'c' {
+		if i+1 < len(word) && strings.IndexByte("eiy", word[i+1]) >= 0 {
+			return "c", []string{"ts", "s"}
+		}
+		return "c", []string{"k"}
+	}
+	return word[i : i+1], []string{word[i : i+1]}
+}
+
+// bmApproximate сводит фонетическую запись к приблизительной форме и убирает повторы
+func bmApproximate(phonetic string) string {
+	var sb strings.Builder
+	var last rune
+	for _, r := range phonetic {
+		sound, ok := bmApprox[r]
+		if !ok {
+			sound = string(r)
+		}
+		for _, s := range sound {
+			if s != last {
+				sb.WriteRune(s)
+			}
+			last = s
+		}
+	}
+	return sb.String()
+}
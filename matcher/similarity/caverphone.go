@@ -0,0 +1,65 @@
+package similarity
+
+import (
+	"regexp"
+	"strings"
+)
+
+// caverphoneRule правило замены алгоритма Caverphone
+type caverphoneRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// caverphoneRules правила Caverphone 2.0 в порядке применения
+var caverphoneRules = compileCaverphoneRules([][2]string{
+	// Начало и конец слова
+	{`^cough`, "cou2f"}, {`^rough`, "rou2f"}, {`^tough`, "tou2f"},
+	{`^enough`, "enou2f"}, {`^trough`, "trou2f"}, {`^gn`, "2n"}, {`mb$`, "m2"},
+	// Буквосочетания
+	{`cq`, "2q"}, {`ci`, "si"}, {`ce`, "se"}, {`cy`, "sy"}, {`tch`, "2ch"},
+	{`c`, "k"}, {`q`, "k"}, {`x`, "k"}, {`v`, "f"}, {`dg`, "2g"},
+	{`tio`, "sio"}, {`tia`, "sia"}, {`d`, "t"}, {`ph`, "fh"}, {`b`, "p"},
+	{`sh`, "s2"}, {`z`, "s"},
+	// Гласные
+	{`^[aeiou]`, "A"}, {`[aeiou]`, "3"},
+	{`j`, "y"}, {`^y3`, "Y3"}, {`^y`, "A"}, {`y`, "3"},
+	{`3gh3`, "3kh3"}, {`gh`, "22"}, {`g`, "k"},
+	// Повторяющиеся согласные
+	{`s+`, "S"}, {`t+`, "T"}, {`p+`, "P"}, {`k+`, "K"}, {`f+`, "F"}, {`m+`, "M"}, {`n+`, "N"},
+	{`w3`, "W3"}, {`wh3`, "Wh3"}, {`w$`, "3"}, {`w`, "2"},
+	{`^h`, "A"}, {`h`, "2"},
+	{`r3`, "R3"}, {`r$`, "3"}, {`r`, "2"},
+	{`l3`, "L3"}, {`l$`, "3"}, {`l`, "2"},
+	// Завершение
+	{`2`, ""}, {`3$`, "A"}, {`3`, ""},
+})
+
+// caverphoneLength длина кода Caverphone 2.0
+const caverphoneLength = 10
+
+// Caverphone2 реализует алгоритм Caverphone 2.0. Код дополняется единицами
+// до 10 символов; кириллица предварительно транслитерируется по ГОСТ
+func Caverphone2(word string) string {
+	// Конечная e не произносится
+	word = strings.TrimSuffix(strings.ToLower(asciiWord(word)), "e")
+	if word == "" {
+		return ""
+	}
+
+	for _, rule := range caverphoneRules {
+		word = rule.pattern.ReplaceAllString(word, rule.replacement)
+	}
+
+	word += strings.Repeat("1", caverphoneLength)
+	return word[:caverphoneLength]
+}
+
+// compileCaverphoneRules компилирует правила Caverphone
+func compileCaverphoneRules(rules [][2]string) []caverphoneRule {
+	compiled := make([]caverphoneRule, len(rules))
+	for i, rule := range rules {
+		compiled[i] = caverphoneRule{pattern: regexp.MustCompile(rule[0]), replacement: rule[1]}
+	}
+	return compiled
+}
@@ -0,0 +1,166 @@
+package similarity
+
+import (
+	"sort"
+	"strings"
+)
+
+// dmRule правило Daitch-Mokotoff: коды буквосочетания в начале слова, перед гласной
+// и в остальных случаях. Несколько кодов означают неоднозначное произношение
+type dmRule struct {
+	pattern     string
+	start       []string
+	beforeVowel []string
+	other       []string
+}
+
+// dmTable таблица Daitch-Mokotoff Soundex: буквосочетание, коды в начале слова,
+// перед гласной и в остальных случаях. "-" - буквосочетание не кодируется,
+// "|" разделяет альтернативные коды
+var dmTable = []string{
+	"AI 0 1 -", "AJ 0 1 -", "AY 0 1 -", "AU 0 7 -", "A 0 - -",
+	"B 7 7 7",
+	"CHS 5 54 54", "CH 5|4 5|4 5|4", "CK 5|45 5|45 5|45",
+	"CZS 4 4 4", "CZ 4 4 4", "CSZ 4 4 4", "CS 4 4 4", "C 5|4 5|4 5|4",
+	"DRZ 4 4 4", "DRS 4 4 4", "DSH 4 4 4", "DSZ 4 4 4", "DS 4 4 4",
+	"DZH 4 4 4", "DZS 4 4 4", "DZ 4 4 4", "DT 3 3 3", "D 3 3 3",
+	"EI 0 1 -", "EJ 0 1 -", "EY 0 1 -", "EU 1 1 -", "E 0 - -",
+	"FB 7 7 7", "F 7 7 7",
+	"G 5 5 5", "H 5 5 -",
+	"IA 1 - -", "IE 1 - -", "IO 1 - -", "IU 1 - -", "I 0 - -",
+	"J 1|4 -|4 -|4",
+	"KS 5 54 54", "KH 5 5 5", "K 5 5 5",
+	"L 8 8 8",
+	"MN - 66 66", "M 6 6 6", "NM - 66 66", "N 6 6 6",
+	"OI 0 1 -", "OJ 0 1 -", "OY 0 1 -", "O 0 - -",
+	"PF 7 7 7", "PH 7 7 7", "P 7 7 7", "Q 5 5 5",
+	"RZ 94|4 94|4 94|4", "RS 94|4 94|4 94|4", "R 9 9 9",
+	"SCHTSCH 2 4 4", "SCHTSH 2 4 4", "SCHTCH 2 4 4", "SHTCH 2 4 4", "SHTSH 2 4 4",
+	"STSCH 2 4 4", "SCHT 2 43 43", "SCHD 2 43 43", "STRZ 2 4 4", "STRS 2 4 4",
+	"STCH 2 4 4", "STSH 2 4 4", "SZCZ 2 4 4", "SZCS 2 4 4", "SHCH 2 4 4",
+	"SCH 4 4 4", "SHT 2 43 43", "SZT 2 43 43", "SHD 2 43 43", "SZD 2 43 43",
+	"SH 4 4 4", "SC 2 4 4", "ST 2 43 43", "SD 2 43 43", "SZ 4 4 4", "S 4 4 4",
+	"TTSCH 4 4 4", "TTCH 4 4 4", "TTSZ 4 4 4", "TSCH 4 4 4", "TTS 4 4 4", "TTZ 4 4 4",
+	"TCH 4 4 4", "TRZ 4 4 4", "TRS 4 4 4", "TSH 4 4 4", "TZS 4 4 4", "TSZ 4 4 4",
+	"TH 3 3 3", "TS 4 4 4", "TC 4 4 4", "TZ 4 4 4", "T 3 3 3",
+	"UI 0 1 -", "UJ 0 1 -", "UY 0 1 -", "UE 0 - -", "U 0 - -",
+	"V 7 7 7", "W 7 7 7", "X 5 54 54", "Y 1 - -",
+	"ZHDZH 2 4 4", "ZDZH 2 4 4", "ZSCH 4 4 4", "ZDZ 2 4 4", "ZHD 2 43 43",
+	"ZSH 4 4 4", "ZD 2 43 43", "ZH 4 4 4", "ZS 4 4 4", "Z 4 4 4",
+}
+
+// dmRules правила Daitch-Mokotoff, отсортированные по убыванию длины буквосочетания
+var dmRules = parseDMTable(dmTable)
+
+// dmLength длина кода Daitch-Mokotoff
+const dmLength = 6
+
+// dmMaxBranches ограничение числа альтернативных кодов одного слова
+const dmMaxBranches = 32
+
+// dmBranch вариант кода, построенный при одном выборе альтернатив
+type dmBranch struct {
+	code string
+	last string
+}
+
+// DaitchMokotoff реализует Daitch-Mokotoff Soundex для восточноевропейских и еврейских
+// фамилий. Возвращает отсортированные шестизначные коды: буквосочетания с неоднозначным
+// произношением ("CH", "RZ", "J") дают несколько кодов. Кириллица предварительно
+// транслитерируется по ГОСТ
+func DaitchMokotoff(word string) []string {
+	word = asciiWord(word)
+	if word == "" {
+		return nil
+	}
+
+	branches := []dmBranch{{}}
+	for i := 0; i < len(word); {
+		rule := dmMatch(word, i)
+		if rule == nil {
+			i++
+			continue
+		}
+		end := i + len(rule.pattern)
+
+		codes := rule.other
+		switch {
+		case i == 0:
+			codes = rule.start
+		case end < len(word) && strings.IndexByte("AEIOU", word[end]) >= 0:
+			codes = rule.beforeVowel
+		}
+
+		next := make([]dmBranch, 0, len(branches)*len(codes))
+		for _, branch := range branches {
+			for _, code := range codes {
+				if len(next) >= dmMaxBranches {
+					break
+				}
+				// Одинаковые коды соседних буквосочетаний не повторяются;
+				// некодируемое буквосочетание (гласная) разделяет их
+				extended := dmBranch{code: branch.code, last: code}
+				if code != "" && !(branch.last != "" && strings.HasSuffix(branch.last, code)) {
+					extended.code += code
+				}
+				next = append(next, extended)
+			}
+		}
+		branches = next
+		i = end
+	}
+
+	seen := make(map[string]bool, len(branches))
+	result := make([]string, 0, len(branches))
+	for _, branch := range branches {
+		code := (branch.code + strings.Repeat("0", dmLength))[:dmLength]
+		if !seen[code] {
+			seen[code] = true
+			result = append(result, code)
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// dmMatch возвращает самое длинное правило, буквосочетание которого начинается с позиции i
+func dmMatch(word string, i int) *dmRule {
+	for j := range dmRules {
+		if strings.HasPrefix(word[i:], dmRules[j].pattern) {
+			return &dmRules[j]
+		}
+	}
+	return nil
+}
+
+// parseDMTable разбирает таблицу Daitch-Mokotoff
+func parseDMTable(table []string) []dmRule {
+	rules := make([]dmRule, 0, len(table))
+	for _, line := range table {
+		fields := strings.Fields(line)
+		rules = append(rules, dmRule{
+			pattern:     fields[0],
+			start:       parseDMCodes(fields[1]),
+			beforeVowel: parseDMCodes(fields[2]),
+			other:       parseDMCodes(fields[3]),
+		})
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		return len(rules[i].pattern) > len(rules[j].pattern)
+	})
+
+	return rules
+}
+
+// parseDMCodes разбирает альтернативные коды; "-" означает пустой код
+func parseDMCodes(field string) []string {
+	codes := strings.Split(field, "|")
+	for i, code := range codes {
+		if code == "-" {
+			codes[i] = ""
+		}
+	}
+	return codes
+}
@@ -12,8 +12,8 @@ func DoubleMetaphoneSimilarity(s1, s2 string) float64 {
 	}
 
 	// Разбиваем строки на слова
-	words1 := strings.Fields(s1)
-	words2 := strings.Fields(s2)
+	words1 := Tokenize(s1)
+	words2 := Tokenize(s2)
 
 	// Если нет слов - возвращаем 0
 	if len(words1) == 0 || len(words2) == 0 {
@@ -33,9 +33,9 @@ func DoubleMetaphoneSimilarity(s1, s2 string) float64 {
 			primary2, secondary2 := DoubleMetaphone(word2)
 
 			// Проверяем совпадение по первичным и вторичным кодам
-			if (primary1 == primary2 || primary1 == secondary2 ||
-				secondary1 == primary2 || secondary1 == secondary2) &&
-				primary1 != "" && primary2 != "" {
+			if primary1 != "" && primary2 != "" && (primary1 == primary2 || primary1 == secondary2 ||
+				secondary1 == primary2 || secondary1 != "" && secondary1 == secondary2 ||
+				initialMetaphone(word1, primary1, primary2) || initialMetaphone(word2, primary2, primary1)) {
 				matchCount++
 				break
 			}
@@ -46,6 +46,11 @@ func DoubleMetaphoneSimilarity(s1, s2 string) float64 {
 	return float64(matchCount) / float64(totalWords)
 }
 
+// initialMetaphone проверяет, что однобуквенное слово (инициал) совпадает с началом кода другого слова
+func initialMetaphone(word, code, other string) bool {
+	return len([]rune(word)) == 1 && strings.HasPrefix(other, code)
+}
+
 // DoubleMetaphone реализует алгоритм Double Metaphone
 // Возвращает два кода - первичный и вторичный
 func DoubleMetaphone(word string) (string, string) {
@@ -0,0 +1,189 @@
+package similarity
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/x0rium/compareNames/matcher/translit"
+)
+
+// Имена встроенных фонетических кодировщиков
+const (
+	EncoderSoundex         = "soundex"
+	EncoderRussian         = "russian"
+	EncoderDoubleMetaphone = "double_metaphone"
+	EncoderNYSIIS          = "nysiis"
+	EncoderCaverphone      = "caverphone"
+	EncoderDaitchMokotoff  = "daitch_mokotoff"
+	EncoderBeiderMorse     = "beider_morse"
+)
+
+// PhoneticEncoder фонетический кодировщик слов
+type PhoneticEncoder interface {
+	// Encode возвращает фонетические коды слова. Слова звучат похоже, если у них
+	// есть общий код; пустой результат означает, что слово не кодируется
+	Encode(word string) []string
+}
+
+// EncoderFunc кодировщик из функции, возвращающей один код
+type EncoderFunc func(string) string
+
+// Encode возвращает код слова или nil для пустого кода
+func (f EncoderFunc) Encode(word string) []string {
+	code := f(word)
+	if code == "" {
+		return nil
+	}
+	return []string{code}
+}
+
+// Реестр фонетических кодировщиков
+var (
+	encoders = map[string]PhoneticEncoder{
+		EncoderSoundex:         EncoderFunc(RussianSoundex),
+		EncoderRussian:         EncoderFunc(RussianPhonetic),
+		EncoderDoubleMetaphone: doubleMetaphoneEncoder{},
+		EncoderNYSIIS:          EncoderFunc(NYSIIS),
+		EncoderCaverphone:      EncoderFunc(Caverphone2),
+		EncoderDaitchMokotoff:  encoderFuncs(DaitchMokotoff),
+		EncoderBeiderMorse:     encoderFuncs(BeiderMorse),
+	}
+	encodersMutex sync.RWMutex
+)
+
+// encoderFuncs кодировщик из функции, возвращающей несколько кодов
+type encoderFuncs func(string) []string
+
+// Encode возвращает коды слова
+func (f encoderFuncs) Encode(word string) []string {
+	return f(word)
+}
+
+// doubleMetaphoneEncoder кодировщик Double Metaphone: первичный и вторичный коды
+type doubleMetaphoneEncoder struct{}
+
+// Encode возвращает непустые коды Double Metaphone
+func (doubleMetaphoneEncoder) Encode(word string) []string {
+	primary, secondary := DoubleMetaphone(word)
+	var codes []string
+	if primary != "" {
+		codes = append(codes, primary)
+	}
+	if secondary != "" && secondary != primary {
+		codes = append(codes, secondary)
+	}
+	return codes
+}
+
+// RegisterEncoder регистрирует фонетический кодировщик под указанным именем.
+// Кодировщик с тем же именем, в том числе встроенный, заменяется
+func RegisterEncoder(name string, encoder PhoneticEncoder) {
+	encodersMutex.Lock()
+	defer encodersMutex.Unlock()
+
+	encoders[name] = encoder
+}
+
+// LookupEncoder возвращает фонетический кодировщик по имени
+func LookupEncoder(name string) (PhoneticEncoder, bool) {
+	encodersMutex.RLock()
+	defer encodersMutex.RUnlock()
+
+	encoder, ok := encoders[name]
+	return encoder, ok
+}
+
+// Encoders возвращает отсортированный список имен зарегистрированных кодировщиков
+func Encoders() []string {
+	encodersMutex.RLock()
+	defer encodersMutex.RUnlock()
+
+	names := make([]string, 0, len(encoders))
+	for name := range encoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// EncoderSimilarity вычисляет долю слов, для которых во второй строке есть слово
+// с общим фонетическим кодом
+func EncoderSimilarity(encoder PhoneticEncoder, s1, s2 string) float64 {
+	words1 := Tokenize(s1)
+	words2 := Tokenize(s2)
+	if len(words1) == 0 || len(words2) == 0 {
+		return 0.0
+	}
+
+	codes2 := make([][]string, len(words2))
+	for i, word := range words2 {
+		codes2[i] = encoder.Encode(word)
+	}
+
+	matchCount := 0
+	for _, word1 := range words1 {
+		codes1 := encoder.Encode(word1)
+		for _, codes := range codes2 {
+			if shareCode(codes1, codes) {
+				matchCount++
+				break
+			}
+		}
+	}
+
+	return float64(matchCount) / float64(max(len(words1), len(words2)))
+}
+
+// WeightedEncoderSimilarity вычисляет взвешенное среднее оценок EncoderSimilarity
+// для кодировщиков из набора. Неизвестные кодировщики и неположительные веса пропускаются
+func WeightedEncoderSimilarity(s1, s2 string, weights map[string]float64) float64 {
+	totalWeight, score := 0.0, 0.0
+	for name, weight := range weights {
+		encoder, ok := LookupEncoder(name)
+		if !ok || weight <= 0 {
+			continue
+		}
+		score += EncoderSimilarity(encoder, s1, s2) * weight
+		totalWeight += weight
+	}
+
+	if totalWeight == 0 {
+		return 0.0
+	}
+	return score / totalWeight
+}
+
+// shareCode проверяет, есть ли у двух наборов общий непустой код
+func shareCode(codes1, codes2 []string) bool {
+	for _, c1 := range codes1 {
+		if c1 == "" {
+			continue
+		}
+		for _, c2 := range codes2 {
+			if c1 == c2 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// asciiWord подготавливает слово для латинских фонетических алгоритмов: кириллица
+// транслитерируется по ГОСТ, диакритика снимается, остаются только буквы A-Z в верхнем регистре
+func asciiWord(word string) string {
+	word = strings.ToLower(word)
+	if translit.IsCyrillic(word) {
+		word = translit.TranslitGOST(word)
+	}
+	word = translit.FoldDiacritics(word)
+
+	var sb strings.Builder
+	for _, r := range word {
+		if r >= 'a' && r <= 'z' {
+			sb.WriteRune(r - 'a' + 'A')
+		}
+	}
+	return sb.String()
+}
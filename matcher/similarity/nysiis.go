@@ -0,0 +1,99 @@
+package similarity
+
+import "strings"
+
+// nysiisMaxLength длина кода NYSIIS в строгом варианте алгоритма
+const nysiisMaxLength = 6
+
+// NYSIIS реализует алгоритм New York State Identification and Intelligence System.
+// Код не длиннее 6 символов; кириллица предварительно транслитерируется по ГОСТ
+func NYSIIS(word string) string {
+	word = asciiWord(word)
+	if word == "" {
+		return ""
+	}
+
+	// Замены в начале слова
+	for _, prefix := range [][2]string{{"MAC", "MCC"}, {"KN", "NN"}, {"K", "C"}, {"PH", "FF"}, {"PF", "FF"}, {"SCH", "SSS"}} {
+		if strings.HasPrefix(word, prefix[0]) {
+			word = prefix[1] + word[len(prefix[0]):]
+			break
+		}
+	}
+
+	// Замены в конце слова
+	for _, suffix := range [][2]string{{"EE", "Y"}, {"IE", "Y"}, {"DT", "D"}, {"RT", "D"}, {"RD", "D"}, {"NT", "D"}, {"ND", "D"}} {
+		if strings.HasSuffix(word, suffix[0]) {
+			word = word[:len(word)-len(suffix[0])] + suffix[1]
+			break
+		}
+	}
+
+	chars := []byte(word)
+	key := []byte{chars[0]}
+	for i := 1; i < len(chars); i++ {
+		next, afterNext := byte(' '), byte(' ')
+		if i+1 < len(chars) {
+			next = chars[i+1]
+		}
+		if i+2 < len(chars) {
+			afterNext = chars[i+2]
+		}
+
+		// Замена может затрагивать следующие буквы (EV -> AF, SCH -> SSS)
+		copy(chars[i:], nysiisTranscode(chars[i-1], chars[i], next, afterNext))
+		if chars[i] != chars[i-1] {
+			key = append(key, chars[i])
+		}
+	}
+
+	// Завершающие S и A отбрасываются, AY заменяется на Y
+	if len(key) > 1 && key[len(key)-1] == 'S' {
+		key = key[:len(key)-1]
+	}
+	if len(key) > 2 && key[len(key)-2] == 'A' && key[len(key)-1] == 'Y' {
+		key = append(key[:len(key)-2], 'Y')
+	}
+	if len(key) > 1 && key[len(key)-1] == 'A' {
+		key = key[:len(key)-1]
+	}
+
+	if len(key) > nysiisMaxLength {
+		key = key[:nysiisMaxLength]
+	}
+	return string(key)
+}
+
+// nysiisTranscode возвращает замену текущей буквы с учетом соседних
+func nysiisTranscode(prev, curr, next, afterNext byte) []byte {
+	switch {
+	case curr == 'E' && next == 'V':
+		return []byte("AF")
+	case nysiisVowel(curr):
+		return []byte("A")
+	case curr == 'Q':
+		return []byte("G")
+	case curr == 'Z':
+		return []byte("S")
+	case curr == 'M':
+		return []byte("N")
+	case curr == 'K' && next == 'N':
+		return []byte("NN")
+	case curr == 'K':
+		return []byte("C")
+	case curr == 'S' && next == 'C' && afterNext == 'H':
+		return []byte("SSS")
+	case curr == 'P' && next == 'H':
+		return []byte("FF")
+	case curr == 'H' && (!nysiisVowel(prev) || !nysiisVowel(next)):
+		return []byte{prev}
+	case curr == 'W' && nysiisVowel(prev):
+		return []byte{prev}
+	}
+	return []byte{curr}
+}
+
+// nysiisVowel проверяет, является ли буква гласной; в NYSIIS Y гласной не считается
+func nysiisVowel(c byte) bool {
+	return c == 'A' || c == 'E' || c == 'I' || c == 'O' || c == 'U'
+}
@@ -0,0 +1,106 @@
+package similarity
+
+import (
+	"strings"
+
+	"github.com/x0rium/compareNames/matcher/translit"
+)
+
+// Классы букв для русского фонетического кодирования
+const (
+	russianVoiceless = "пфктшсхцчщ"
+	russianSoftSigns = "ьъ"
+)
+
+// russianDevoicing пары звонких и глухих согласных
+var russianDevoicing = map[rune]rune{
+	'б': 'п', 'в': 'ф', 'г': 'к', 'д': 'т', 'ж': 'ш', 'з': 'с',
+}
+
+// russianVowels сведение гласных: безударные о/а звучат одинаково, е/ё/э/и/ы/я и й сводятся к и, ю к у
+var russianVowels = map[rune]rune{
+	'а': 'а', 'о': 'а',
+	'е': 'и', 'ё': 'и', 'э': 'и', 'и': 'и', 'ы': 'и', 'я': 'и', 'й': 'и',
+	'у': 'у', 'ю': 'у',
+}
+
+// RussianPhonetic кодирует слово по правилам русского произношения (по мотивам «русского метафона»):
+// гласные о/а и е/ё/э/и/ы/я сводятся к одной гласной, ю к у, звонкие согласные оглушаются в конце
+// слова и перед глухими, -тся/-ться произносится как -ца, мягкий и твердый знаки и повторы букв
+// не учитываются. Слова на латинице предварительно переводятся в кириллицу.
+// Например, "Шевчук" и "Шевчюк" дают "шифчук", "Ковалёв" и "Ковалев" - "кавалиф"
+func RussianPhonetic(word string) string {
+	word = strings.ToLower(word)
+	if !translit.IsCyrillic(word) {
+		word = translit.TranslitGOSTReverse(word)
+	}
+
+	// Оставляем только кириллические буквы
+	var letters []rune
+	for _, r := range word {
+		if translit.ScriptOf(r) == translit.ScriptCyrillic {
+			letters = append(letters, r)
+		}
+	}
+	word = string(letters)
+
+	// -тся и -ться в конце слова произносятся как -ца
+	for _, ending := range []string{"ться", "тся"} {
+		if strings.HasSuffix(word, ending) {
+			word = strings.TrimSuffix(word, ending) + "ца"
+			break
+		}
+	}
+
+	// Йотированные сочетания звучат как и
+	word = strings.NewReplacer("йо", "и", "ио", "и", "йе", "и", "ие", "и").Replace(word)
+
+	letters = []rune(word)
+	var sb strings.Builder
+	var prev rune
+	for i, r := range letters {
+		if strings.ContainsRune(russianSoftSigns, r) {
+			continue
+		}
+
+		code := r
+		if vowel, ok := russianVowels[r]; ok {
+			code = vowel
+		} else if voiceless, ok := russianDevoicing[r]; ok {
+			// Оглушение в конце слова и перед глухой согласной
+			next := nextRussianLetter(letters, i+1)
+			if next == 0 || strings.ContainsRune(russianVoiceless, next) {
+				code = voiceless
+			}
+		}
+
+		if code != prev {
+			sb.WriteRune(code)
+		}
+		prev = code
+	}
+
+	return sb.String()
+}
+
+// nextRussianLetter возвращает следующую за позицией букву, пропуская мягкий и твердый знаки,
+// или 0 в конце слова
+func nextRussianLetter(letters []rune, from int) rune {
+	for _, r := range letters[from:] {
+		if !strings.ContainsRune(russianSoftSigns, r) {
+			return r
+		}
+	}
+	return 0
+}
+
+// RussianPhoneticSimilarity вычисляет долю слов с совпадающими кодами RussianPhonetic
+func RussianPhoneticSimilarity(s1, s2 string) float64 {
+	return EncoderSimilarity(EncoderFunc(RussianPhonetic), s1, s2)
+}
+
+// RussianPhoneticMatch проверяет, совпадают ли коды RussianPhonetic двух слов
+func RussianPhoneticMatch(word1, word2 string) bool {
+	code1 := RussianPhonetic(word1)
+	return code1 != "" && code1 == RussianPhonetic(word2)
+}
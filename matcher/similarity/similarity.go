@@ -3,28 +3,32 @@ package similarity
 import (
 	"math"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/x0rium/compareNames/matcher/translit"
 )
 
 // StringVector представляет вектор n-грамм для строки
 type StringVector map[string]int
 
-// LevenshteinDistance вычисляет расстояние Левенштейна между двумя строками
+// LevenshteinDistance вычисляет расстояние Левенштейна между двумя строками.
+// Строки сравниваются по символам (рунам), поэтому кириллическая буква - одна правка, а не две
 func LevenshteinDistance(s1, s2 string) int {
 	// Приведение к нижнему регистру для игнорирования регистра
-	s1, s2 = strings.ToLower(s1), strings.ToLower(s2)
+	r1, r2 := []rune(strings.ToLower(s1)), []rune(strings.ToLower(s2))
 
 	// Если одна из строк пустая, расстояние равно длине другой строки
-	if len(s1) == 0 {
-		return len(s2)
+	if len(r1) == 0 {
+		return len(r2)
 	}
-	if len(s2) == 0 {
-		return len(s1)
+	if len(r2) == 0 {
+		return len(r1)
 	}
 
 	// Создаем матрицу для динамического программирования
-	matrix := make([][]int, len(s1)+1)
+	matrix := make([][]int, len(r1)+1)
 	for i := range matrix {
-		matrix[i] = make([]int, len(s2)+1)
+		matrix[i] = make([]int, len(r2)+1)
 		matrix[i][0] = i // Заполняем первый столбец
 	}
 	for j := range matrix[0] {
@@ -32,10 +36,10 @@ func LevenshteinDistance(s1, s2 string) int {
 	}
 
 	// Заполняем матрицу
-	for i := 1; i <= len(s1); i++ {
-		for j := 1; j <= len(s2); j++ {
+	for i := 1; i <= len(r1); i++ {
+		for j := 1; j <= len(r2); j++ {
 			cost := 1
-			if s1[i-1] == s2[j-1] {
+			if r1[i-1] == r2[j-1] {
 				cost = 0
 			}
 			matrix[i][j] = min(
@@ -46,93 +50,84 @@ func LevenshteinDistance(s1, s2 string) int {
 		}
 	}
 
-	return matrix[len(s1)][len(s2)]
+	return matrix[len(r1)][len(r2)]
 }
 
 // LevenshteinSimilarity вычисляет оценку схожести на основе расстояния Левенштейна
 // Возвращает число от 0 до 1, где 1 означает полное совпадение
 func LevenshteinSimilarity(s1, s2 string) float64 {
-	if len(s1) == 0 && len(s2) == 0 {
+	r1, r2 := []rune(strings.ToLower(s1)), []rune(strings.ToLower(s2))
+	if len(r1) == 0 && len(r2) == 0 {
 		return 1.0
 	}
-	if len(s1) == 0 || len(s2) == 0 {
+	if len(r1) == 0 || len(r2) == 0 {
 		return 0.0
 	}
 
 	// Для коротких строк (<=3 символа) - специальная обработка
-	if len(s1) <= 3 || len(s2) <= 3 {
-		if strings.EqualFold(s1, s2) {
+	if len(r1) <= 3 || len(r2) <= 3 {
+		if string(r1) == string(r2) {
 			return 1.0
 		}
 		return 0.0
 	}
 
 	// Префиксное совпадение (для бонуса)
-	prefixLen := 0
-	for i := 0; i < min(len(s1), len(s2)); i++ {
-		if strings.EqualFold(string(s1[i]), string(s2[i])) {
-			prefixLen++
-		} else {
-			break
-		}
-	}
+	prefixLen := commonPrefixLength(r1, r2, len(r1))
 	prefixBonus := float64(prefixLen) * 0.1 // 0.1 - это масштабный коэффициент для префикса
 
 	// Основная оценка Левенштейна
 	distance := LevenshteinDistance(s1, s2)
-	maxLen := max(len(s1), len(s2))
+	maxLen := max(len(r1), len(r2))
 	baseScore := 1.0 - float64(distance)/float64(maxLen)
 
 	// Возвращаем финальную оценку с бонусом за префикс, не превышая 1.0
 	return math.Min(1.0, baseScore+prefixBonus)
 }
 
-// JaroSimilarity вычисляет схожесть Джаро между двумя строками
+// JaroSimilarity вычисляет схожесть Джаро между двумя строками по символам (рунам)
 func JaroSimilarity(s1, s2 string) float64 {
 	// Приведение к нижнему регистру для игнорирования регистра
-	s1, s2 = strings.ToLower(s1), strings.ToLower(s2)
+	r1, r2 := []rune(strings.ToLower(s1)), []rune(strings.ToLower(s2))
 
 	// Проверка на пустые строки
-	if len(s1) == 0 && len(s2) == 0 {
+	if len(r1) == 0 && len(r2) == 0 {
 		return 1.0
 	}
-	if len(s1) == 0 || len(s2) == 0 {
+	if len(r1) == 0 || len(r2) == 0 {
 		return 0.0
 	}
 
 	// Если строки идентичны, возвращаем 1.0
-	if s1 == s2 {
+	if string(r1) == string(r2) {
 		return 1.0
 	}
 
 	// Для коротких строк - специальная обработка
-	if len(s1) <= 3 || len(s2) <= 3 {
-		if strings.EqualFold(s1, s2) {
-			return 1.0
-		}
+	if len(r1) <= 3 || len(r2) <= 3 {
 		return 0.0
 	}
 
 	// Вычисляем расстояние для поиска совпадений
-	matchDistance := max(len(s1), len(s2))/2 - 1
+	matchDistance := max(len(r1), len(r2))/2 - 1
 	if matchDistance < 0 {
 		matchDistance = 0
 	}
 
 	// Отмечаем символы, которые совпадают
-	matches1 := make([]bool, len(s1))
-	matches2 := make([]bool, len(s2))
+	matches1 := make([]bool, len(r1))
+	matches2 := make([]bool, len(r2))
 	matchCount := 0
 
 	// Находим совпадающие символы
-	for i := 0; i < len(s1); i++ {
+	for i := 0; i < len(r1); i++ {
 		// Вычисляем нижнюю и верхнюю границы для поиска
 		start := max(0, i-matchDistance)
-		end := min(i+matchDistance+1, len(s2))
+		end := min(i+matchDistance+1, len(r2))
 
 		for j := start; j < end; j++ {
 			// Если символ уже совпал с другим или не совпадает - пропускаем
-			if matches2[j] || s1[i] != s2[j] {
+			if matches2[j] || r1[i] != r2[j] {
 				continue
 			}
 			// Отмечаем совпадения
@@ -151,7 +146,7 @@ func JaroSimilarity(s1, s2 string) float64 {
 	// Подсчитываем транспозиции (количество символов, которые не на своих местах)
 	transpositions := 0
 	j := 0
-	for i := 0; i < len(s1); i++ {
+	for i := 0; i < len(r1); i++ {
 		if !matches1[i] {
 			continue
 		}
@@ -160,7 +155,7 @@ func JaroSimilarity(s1, s2 string) float64 {
 			j++
 		}
 		// Если символы не совпадают, увеличиваем счетчик транспозиций
-		if s1[i] != s2[j] {
+		if r1[i] != r2[j] {
 			transpositions++
 		}
 		j++
@@ -170,8 +165,8 @@ func JaroSimilarity(s1, s2 string) float64 {
 	transpositions /= 2
 
 	// Вычисляем метрику Джаро
-	return (float64(matchCount)/float64(len(s1)) +
-		float64(matchCount)/float64(len(s2)) +
+	return (float64(matchCount)/float64(len(r1)) +
+		float64(matchCount)/float64(len(r2)) +
 		float64(matchCount-transpositions)/float64(matchCount)) / 3.0
 }
 
@@ -185,18 +180,8 @@ func JaroWinklerSimilarity(s1, s2 string) float64 {
 		return jaro
 	}
 
-	// Приводим к нижнему регистру для корректного сравнения
-	s1Lower, s2Lower := strings.ToLower(s1), strings.ToLower(s2)
-
 	// Находим длину общего префикса (максимум 4 символа)
-	prefixLen := 0
-	for i := 0; i < min(len(s1Lower), len(s2Lower), 4); i++ {
-		if s1Lower[i] == s2Lower[i] {
-			prefixLen++
-		} else {
-			break
-		}
-	}
+	prefixLen := commonPrefixLength([]rune(strings.ToLower(s1)), []rune(strings.ToLower(s2)), 4)
 
 	// Константа масштабирования для Винклера (обычно 0.1)
 	p := 0.1
@@ -205,14 +190,25 @@ func JaroWinklerSimilarity(s1, s2 string) float64 {
 	return jaro + float64(prefixLen)*p*(1-jaro)
 }
 
+// commonPrefixLength возвращает длину общего префикса последовательностей символов, но не больше limit
+func commonPrefixLength(r1, r2 []rune, limit int) int {
+	n := min(len(r1), len(r2), limit)
+	for i := 0; i < n; i++ {
+		if r1[i] != r2[i] {
+			return i
+		}
+	}
+	return n
+}
+
 // CosineSimilarity вычисляет косинусное сходство между двумя строками
 // используя n-граммы символов (по умолчанию триграммы)
 func CosineSimilarity(s1, s2 string, gramSize int) float64 {
 	// Проверка на пустые строки
-	if len(s1) == 0 && len(s2) == 0 {
+	if s1 == "" && s2 == "" {
 		return 1.0
 	}
-	if len(s1) == 0 || len(s2) == 0 {
+	if s1 == "" || s2 == "" {
 		return 0.0
 	}
 
@@ -233,21 +229,22 @@ func CosineSimilarity(s1, s2 string, gramSize int) float64 {
 	return computeCosineSimilarity(vec1, vec2)
 }
 
-// Создает вектор n-грамм для строки
+// Создает вектор n-грамм (по символам) для строки
 func createNGramVector(s string, n int) StringVector {
 	vector := make(StringVector)
+	runes := []rune(s)
 
 	// Корректируем размер n-граммы, если строка короче n
-	if len(s) < n {
-		n = len(s)
+	if len(runes) < n {
+		n = len(runes)
 		if n == 0 {
 			return vector
 		}
 	}
 
 	// Создаем n-граммы и подсчитываем их частоту
-	for i := 0; i <= len(s)-n; i++ {
-		ngram := s[i : i+n]
+	for i := 0; i <= len(runes)-n; i++ {
+		ngram := string(runes[i : i+n])
 		vector[ngram]++
 	}
 
@@ -289,13 +286,9 @@ func computeCosineSimilarity(vec1, vec2 StringVector) float64 {
 // PhoneticSimilarity вычисляет фонетическую схожесть двух строк
 // с использованием комбинации фонетических алгоритмов
 func PhoneticSimilarity(s1, s2 string) float64 {
-	if len(s1) == 0 || len(s2) == 0 {
-		return 0.0
-	}
-
 	// Разбиваем строки на слова
-	words1 := strings.Fields(s1)
-	words2 := strings.Fields(s2)
+	words1 := Tokenize(s1)
+	words2 := Tokenize(s2)
 
 	// Если нет слов - возвращаем 0
 	if len(words1) == 0 || len(words2) == 0 {
@@ -313,11 +306,13 @@ func PhoneticSimilarity(s1, s2 string) float64 {
 	} else {
 		// Транслитерация и сравнение
 		baseScore = translitPhoneticCompare(words1, words2)
+		words1, words2 = latinWords(words1), latinWords(words2)
 	}
 
 	// Добавляем бонус за совпадение первых букв
-	if len(words1) > 0 && len(words2) > 0 &&
-		strings.EqualFold(string(words1[0][0]), string(words2[0][0])) {
+	first1, _ := utf8.DecodeRuneInString(words1[0])
+	first2, _ := utf8.DecodeRuneInString(words2[0])
+	if first1 == first2 {
 		baseScore += 0.1
 	}
 
@@ -329,18 +324,9 @@ func PhoneticSimilarity(s1, s2 string) float64 {
 	return baseScore
 }
 
-// Проверяет, используют ли строки один и тот же алфавит
+// Проверяет, используют ли строки один и тот же алфавит (см. translit.DetectScripts)
 func isSameScript(s1, s2 string) bool {
-	// Упрощенная проверка - сравниваем первые символы
-	if len(s1) == 0 || len(s2) == 0 {
-		return true // Условно считаем, что пустые строки в одном алфавите
-	}
-
-	// Проверяем, оба ли символа ASCII или оба не ASCII
-	isASCII1 := s1[0] < 128
-	isASCII2 := s2[0] < 128
-
-	return isASCII1 == isASCII2
+	return translit.SameScripts(s1, s2)
 }
 
 // Прямое фонетическое сравнение без транслитерации
@@ -362,11 +348,48 @@ func directPhoneticCompare(words1, words2 []string) float64 {
 	return float64(matches) / float64(totalPairs)
 }
 
-// Фонетическое сравнение с транслитерацией
+// Фонетическое сравнение с транслитерацией: слова не на латинице сравниваются
+// всеми вариантами транслитерации
 func translitPhoneticCompare(words1, words2 []string) float64 {
-	// В этой функции можно использовать транслитерацию
-	// Для простоты используем прямое сравнение
-	return directPhoneticCompare(words1, words2)
+	matches := 0
+	totalPairs := max(len(words1), len(words2))
+
+	for _, word1 := range words1 {
+		variants1 := translit.GetAllTransliterations(word1)
+		for _, word2 := range words2 {
+			if translitWordsClose(variants1, translit.GetAllTransliterations(word2)) {
+				matches++
+				break
+			}
+		}
+	}
+
+	return float64(matches) / float64(totalPairs)
+}
+
+// translitWordsClose проверяет, близок ли фонетически хотя бы один вариант первого слова
+// хотя бы к одному варианту второго
+func translitWordsClose(variants1, variants2 []string) bool {
+	for _, v1 := range variants1 {
+		for _, v2 := range variants2 {
+			if strings.EqualFold(v1, v2) || arePhoneticallyClose(v1, v2) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// latinWords транслитерирует кириллические слова по ГОСТ, остальные оставляет без изменений
+func latinWords(words []string) []string {
+	result := make([]string, len(words))
+	for i, word := range words {
+		if translit.IsCyrillic(word) {
+			word = translit.TranslitGOST(word)
+		}
+		result[i] = word
+	}
+	return result
 }
 
 // Проверяет, фонетически близки ли слова
@@ -375,19 +398,20 @@ func arePhoneticallyClose(word1, word2 string) bool {
 	// 1. Первые буквы совпадают
 	// 2. Длины отличаются не более чем на 2 символа
 	// 3. Расстояние Левенштейна <= 2
+	r1, r2 := []rune(strings.ToLower(word1)), []rune(strings.ToLower(word2))
 
 	// Проверка на пустые слова
-	if len(word1) == 0 || len(word2) == 0 {
+	if len(r1) == 0 || len(r2) == 0 {
 		return false
 	}
 
 	// Первые буквы должны совпадать
-	if !strings.EqualFold(string(word1[0]), string(word2[0])) {
+	if r1[0] != r2[0] {
 		return false
 	}
 
 	// Длины не должны отличаться более чем на 2 символа
-	if abs(len(word1)-len(word2)) > 2 {
+	if abs(len(r1)-len(r2)) > 2 {
 		return false
 	}
 
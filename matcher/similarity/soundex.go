@@ -9,16 +9,15 @@ import (
 
 // Soundex реализует алгоритм Soundex для английских слов
 func Soundex(word string) string {
-	if len(word) == 0 {
+	// Приводим к верхнему регистру; буквы перебираем по рунам, а не по байтам
+	runes := []rune(strings.ToUpper(word))
+	if len(runes) == 0 {
 		return "0000"
 	}
-
-	// Приводим к верхнему регистру и оставляем только буквы
-	word = strings.ToUpper(word)
 	letters := []rune{'0', '0', '0', '0'}
 
 	// Сохраняем первую букву
-	firstLetter := unicode.ToUpper(rune(word[0]))
+	firstLetter := runes[0]
 	if unicode.IsLetter(firstLetter) {
 		letters[0] = firstLetter
 	} else {
@@ -40,8 +39,8 @@ func Soundex(word string) string {
 	prevCode := '0'
 
 	// Обрабатываем остальные буквы
-	for i := 1; i < len(word) && j < 4; i++ {
-		c := unicode.ToUpper(rune(word[i]))
+	for i := 1; i < len(runes) && j < 4; i++ {
+		c := runes[i]
 
 		// Пропускаем H и W
 		if c == 'H' || c == 'W' {
@@ -85,8 +84,8 @@ func SoundexSimilarity(s1, s2 string) float64 {
 	}
 
 	// Разбиваем строки на слова
-	words1 := strings.Fields(s1)
-	words2 := strings.Fields(s2)
+	words1 := Tokenize(s1)
+	words2 := Tokenize(s2)
 
 	// Если нет слов - возвращаем 0
 	if len(words1) == 0 || len(words2) == 0 {
@@ -135,8 +134,8 @@ func RussianSoundexSimilarity(s1, s2 string) float64 {
 	}
 
 	// Разбиваем строки на слова
-	words1 := strings.Fields(s1)
-	words2 := strings.Fields(s2)
+	words1 := Tokenize(s1)
+	words2 := Tokenize(s2)
 
 	// Если нет слов - возвращаем 0
 	if len(words1) == 0 || len(words2) == 0 {
@@ -0,0 +1,40 @@
+package similarity
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenize разбивает имя на слова в нижнем регистре. Слова разделяются любыми символами,
+// кроме букв, цифр и диакритических знаков; дефис разделяет части двойной фамилии.
+// Апострофы и точки не разделяют слово: "O'Neil" - "oneil", слитно записанные инициалы "И.П." - "ип"
+func Tokenize(name string) []string {
+	var tokens []string
+	var sb strings.Builder
+
+	flush := func() {
+		if sb.Len() > 0 {
+			tokens = append(tokens, sb.String())
+			sb.Reset()
+		}
+	}
+
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r):
+			sb.WriteRune(r)
+		case isApostrophe(r) || r == '.':
+			// Апостроф и точка между инициалами не разделяют слово
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// isApostrophe проверяет, является ли символ апострофом
+func isApostrophe(r rune) bool {
+	return r == '\'' || r == '’' || r == 'ʼ' || r == '`'
+}
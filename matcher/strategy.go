@@ -0,0 +1,100 @@
+package matcher
+
+import (
+	"math"
+	"time"
+
+	"github.com/x0rium/compareNames/matcher/compare"
+	"github.com/x0rium/compareNames/matcher/utils"
+)
+
+// Проверяем, что NameMatcher реализует интерфейс стратегий пакета compare
+var _ compare.NameMatcher = (*NameMatcher)(nil)
+
+// GetCacheKey возвращает ключ кэша для пары имен с атрибутами пакета compare
+func (m *NameMatcher) GetCacheKey(name1, name2 string, attrs compare.MatchAttributes) string {
+	return m.cacheKey(name1, name2, fromCompareAttributes(attrs))
+}
+
+// GetMinExactMatchScore возвращает минимальный балл для совпадения
+func (m *NameMatcher) GetMinExactMatchScore() int {
+	return m.Config.MatchThreshold
+}
+
+// GetMinPossibleMatchScore возвращает минимальный балл для возможного совпадения
+func (m *NameMatcher) GetMinPossibleMatchScore() int {
+	return m.Config.PossibleMatchThreshold
+}
+
+// GetConfig возвращает конфигурацию экземпляра для стратегий пакета compare
+func (m *NameMatcher) GetConfig() compare.ConfigProvider {
+	return m.Config
+}
+
+// matchStrategy сравнивает имена стратегиями пакета compare
+func (m *NameMatcher) matchStrategy(name1, name2 string, attrs Attributes) MatchResult {
+	startTime := time.Now()
+
+	// Точное совпадение определяем и оформляем так же, как и в основном алгоритме
+	if namesEqual(name1, name2) {
+		return m.exactMatchResult(attrs, startTime)
+	}
+
+	// Атрибуты учитываются по правилам конфигурации после сравнения имен
+	r := compare.MatchNames(name1, name2, nil, m)
+
+	// Признак точного совпадения стратегий (например, для инициалов с высокой оценкой)
+	// не переносим: точным считается только совпадение имен после предобработки
+	result := MatchResult{
+		Score:                     r.Score,
+		MatchType:                 r.MatchType,
+		BestMatch1:                r.BestMatch1,
+		BestMatch2:                r.BestMatch2,
+		LevenshteinScore:          r.LevenshteinScore,
+		JaroWinklerScore:          r.JaroWinklerScore,
+		PhoneticScore:             r.PhoneticScore,
+		DoubleMetaphoneScore:      r.DoubleMetaphoneScore,
+		CosineScore:               r.CosineScore,
+		AdditionalAttributesScore: r.AdditionalAttributesScore,
+	}
+
+	// Стратегии не раскладывают оценку на бонусы, поэтому пояснение содержит
+	// итоговую оценку стратегии и сопоставленные части имен
+	result.Explanation = &Explanation{
+		BaseScore:    float64(r.Score) / 100,
+		AlignedParts: m.alignParts(utils.ParseName(name1), utils.ParseName(name2)),
+	}
+
+	if score, ok := m.applyAttributes(float64(r.Score)/100, attrs, &result); ok {
+		result.Explanation.AttributesApplied = true
+		result.Score = int(math.Round(score * 100))
+		if result.Score >= m.Config.MatchThreshold {
+			result.MatchType = "match"
+		} else if result.Score >= m.Config.PossibleMatchThreshold {
+			result.MatchType = "possible_match"
+		} else {
+			result.MatchType = "no_match"
+		}
+	}
+	result.ProcessingTimeMS = time.Since(startTime).Milliseconds()
+
+	// Логируем сомнительные совпадения для дальнейшего анализа
+	if result.MatchType == "possible_match" {
+		LogPossibleMatch(name1, name2, attrs, result)
+	}
+
+	return result
+}
+
+// fromCompareAttributes преобразует атрибуты пакета compare в Attributes
+func fromCompareAttributes(attrs compare.MatchAttributes) Attributes {
+	if attrs == nil {
+		return nil
+	}
+
+	result := make(Attributes, len(attrs))
+	for name, attr := range attrs {
+		result[name] = Attribute{Match: attr.Match}
+	}
+	return result
+}
@@ -5,63 +5,6 @@ import (
 	"strings"
 )
 
-// min находит минимальное из трех чисел
-func min(a, b, c int) int {
-	if a < b {
-		if a < c {
-			return a
-		}
-		return c
-	}
-	if b < c {
-		return b
-	}
-	return c
-}
-
-// levenshteinDistance вычисляет расстояние Левенштейна между двумя строками
-func levenshteinDistance(s, t string) int {
-	// Длины строк
-	m := len([]rune(s))
-	n := len([]rune(t))
-
-	// Создаем двумерный массив для динамического программирования
-	d := make([][]int, m+1)
-	for i := range d {
-		d[i] = make([]int, n+1)
-	}
-
-	// Инициализация первой строки и столбца
-	for i := 0; i <= m; i++ {
-		d[i][0] = i
-	}
-	for j := 0; j <= n; j++ {
-		d[0][j] = j
-	}
-
-	// Перебираем все символы
-	sRunes := []rune(s)
-	tRunes := []rune(t)
-
-	for i := 1; i <= m; i++ {
-		for j := 1; j <= n; j++ {
-			cost := 1
-			if sRunes[i-1] == tRunes[j-1] {
-				cost = 0
-			}
-
-			// Выбираем минимальное значение из трех возможных операций
-			d[i][j] = min(
-				d[i-1][j]+1,      // удаление
-				d[i][j-1]+1,      // вставка
-				d[i-1][j-1]+cost, // замена
-			)
-		}
-	}
-
-	return d[m][n]
-}
-
 // jaroWinklerSimilarity вычисляет сходство Джаро-Винклера между двумя строками
 // Значение от 0 (нет сходства) до 1 (идентичные строки)
 func jaroWinklerSimilarity(s1, s2 string) float64 {
@@ -0,0 +1,126 @@
+package matcher
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/x0rium/compareNames/matcher/translit"
+	"github.com/x0rium/compareNames/matcher/utils"
+)
+
+// StructuredName имя, переданное по частям (например, из отдельных колонок HR- и KYC-систем).
+// Роли частей заданы явно: при сравнении они не угадываются и части не переставляются
+type StructuredName struct {
+	Last   string `json:"last"`
+	First  string `json:"first,omitempty"`
+	Middle string `json:"middle,omitempty"` // Отчество или второе имя
+}
+
+// IsEmpty проверяет, что ни одна часть имени не задана
+func (s StructuredName) IsEmpty() bool {
+	return strings.TrimSpace(s.Last+s.First+s.Middle) == ""
+}
+
+// String возвращает имя в порядке "Фамилия Имя Отчество"
+func (s StructuredName) String() string {
+	return strings.Join(strings.Fields(s.Last+" "+s.First+" "+s.Middle), " ")
+}
+
+// Parsed возвращает разбор имени с ролями из частей: фамилия, имя и отчество.
+// Однобуквенные имя и отчество считаются инициалами
+func (s StructuredName) Parsed() utils.ParsedName {
+	parsed := utils.ParsedName{Confident: true}
+
+	add := func(value, role string) {
+		for _, text := range splitParts(value) {
+			partRole := role
+			if role != utils.RoleSurname && utf8.RuneCountInString(text) == 1 {
+				partRole = utils.RoleInitial
+			}
+			parsed.Parts = append(parsed.Parts, utils.NamePart{Text: text, Role: partRole})
+
+			switch partRole {
+			case utils.RoleSurname:
+				parsed.Surname = strings.TrimSpace(parsed.Surname + " " + text)
+			case utils.RoleGiven:
+				parsed.GivenName = strings.TrimSpace(parsed.GivenName + " " + text)
+			case utils.RolePatronymic:
+				parsed.Patronymic = strings.TrimSpace(parsed.Patronymic + " " + text)
+			case utils.RoleInitial:
+				parsed.Initials = append(parsed.Initials, text)
+			}
+		}
+	}
+	add(s.Last, utils.RoleSurname)
+	add(s.First, utils.RoleGiven)
+	add(s.Middle, utils.RolePatronymic)
+
+	return parsed
+}
+
+// NameInput имя для сравнения: в свободной форме (Text) или по частям (Parts).
+// Если заданы части, текст не используется
+type NameInput struct {
+	Text  string
+	Parts *StructuredName
+}
+
+// IsEmpty проверяет, что имя не задано ни текстом, ни частями
+func (n NameInput) IsEmpty() bool {
+	if n.Parts != nil && !n.Parts.IsEmpty() {
+		return false
+	}
+	return n.Text == ""
+}
+
+// text возвращает имя одной строкой
+func (n NameInput) text() string {
+	if n.Parts != nil && !n.Parts.IsEmpty() {
+		return n.Parts.String()
+	}
+	return n.Text
+}
+
+// replaceWord возвращает имя, в котором слово word заменено на replacement без учета регистра
+func (n NameInput) replaceWord(word, replacement string) NameInput {
+	replace := func(text string) string {
+		words := strings.Fields(text)
+		for i, w := range words {
+			if strings.EqualFold(w, word) {
+				words[i] = replacement
+			}
+		}
+		return strings.Join(words, " ")
+	}
+
+	if n.Parts == nil || n.Parts.IsEmpty() {
+		return NameInput{Text: replace(n.Text)}
+	}
+	parts := StructuredName{Last: replace(n.Parts.Last), First: replace(n.Parts.First), Middle: replace(n.Parts.Middle)}
+	return NameInput{Parts: &parts}
+}
+
+// declared возвращает роли частей, заданные вызывающим, или nil для имени в свободной форме.
+// Буквы-двойники другого алфавита в частях заменяются, как и в тексте имени
+func (n NameInput) declared() *utils.ParsedName {
+	if n.Parts == nil || n.Parts.IsEmpty() {
+		return nil
+	}
+	parts := *n.Parts
+	parts.Last, _ = translit.NormalizeHomoglyphs(parts.Last)
+	parts.First, _ = translit.NormalizeHomoglyphs(parts.First)
+	parts.Middle, _ = translit.NormalizeHomoglyphs(parts.Middle)
+
+	parsed := parts.Parsed()
+	return &parsed
+}
+
+// cacheName возвращает имя для ключа кэша; имена по частям отличаются от того же
+// текста в свободной форме
+func (n NameInput) cacheName() string {
+	if n.Parts == nil || n.Parts.IsEmpty() {
+		return n.Text
+	}
+	return "{" + strconv.Quote(n.Parts.Last) + "|" + strconv.Quote(n.Parts.First) + "|" + strconv.Quote(n.Parts.Middle) + "}"
+}
@@ -0,0 +1,153 @@
+package translit
+
+import (
+	"strings"
+)
+
+// MRZNameLength длина поля имени в машиночитаемой зоне паспорта (TD3)
+const MRZNameLength = 39
+
+// TranslitICAO транслитерация по ICAO Doc 9303 (часть 3), используемая
+// в машиночитаемой зоне паспортов
+func TranslitICAO(text string) string {
+	// Приводим к нижнему регистру
+	text = strings.ToLower(text)
+
+	mapping := map[rune]string{
+		// Русские буквы
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e",
+		'ё': "e", 'ж': "zh", 'з': "z", 'и': "i", 'й': "i", 'к': "k",
+		'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+		'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+		'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "ie", 'ы': "y", 'ь': "",
+		'э': "e", 'ю': "iu", 'я': "ia",
+
+		// Украинские и белорусские буквы
+		'є': "ie", 'і': "i", 'ї': "i", 'ґ': "g", 'ў': "u",
+	}
+
+	return transliterate(text, mapping)
+}
+
+// TranslitICAOReverse обратная транслитерация с латиницы на кириллицу по ICAO Doc 9303.
+// Принимает также написание из MRZ с заполнителем '<'
+func TranslitICAOReverse(text string) string {
+	// Приводим к нижнему регистру и убираем заполнители MRZ
+	text = strings.ToLower(FromMRZ(text))
+
+	// Сначала заменяем многосимвольные комбинации, начиная с самых длинных
+	replacements := []struct{ latin, cyrillic string }{
+		{"shch", "щ"},
+		{"zh", "ж"}, {"kh", "х"}, {"ts", "ц"}, {"ch", "ч"}, {"sh", "ш"},
+		{"iu", "ю"}, {"ia", "я"},
+	}
+	for _, r := range replacements {
+		text = strings.ReplaceAll(text, r.latin, r.cyrillic)
+	}
+
+	// Затем заменяем одиночные символы
+	mapping := map[rune]string{
+		'a': "а", 'b': "б", 'v': "в", 'g': "г", 'd': "д", 'e': "е",
+		'z': "з", 'i': "и", 'k': "к", 'l': "л", 'm': "м", 'n': "н",
+		'o': "о", 'p': "п", 'r': "р", 's': "с", 't': "т", 'u': "у",
+		'f': "ф", 'y': "ы",
+	}
+
+	// Окончание "ii" в MRZ обычно соответствует "ий" ("IURII" - "Юрий")
+	if strings.HasSuffix(text, "ii") {
+		text = strings.TrimSuffix(text, "ii") + "ий"
+	}
+
+	return transliterate(text, mapping)
+}
+
+// GetICAOVariations возвращает возможные вариации транслитерации по ICAO Doc 9303,
+// включая написания из паспортов, выданных до 2015 года (ГОСТ Р 52535.1-2006)
+func GetICAOVariations(text string) []string {
+	if !IsCyrillic(text) {
+		// Если текст не на кириллице, пытаемся выполнить обратную транслитерацию
+		return []string{TranslitICAOReverse(text)}
+	}
+
+	base := TranslitICAO(text)
+	variations := []string{base}
+
+	lower := strings.ToLower(text)
+
+	// ГОСТ Р 52535.1-2006: "ц" - "tc", "ъ" не передается
+	if strings.ContainsRune(lower, 'ц') {
+		variations = append(variations, strings.ReplaceAll(base, "ts", "tc"))
+	}
+	if strings.ContainsRune(lower, 'ъ') {
+		variations = append(variations, TranslitICAO(strings.ReplaceAll(lower, "ъ", "")))
+	}
+
+	// Окончание "ий" в старых паспортах передавалось как "y" или "iy"
+	if strings.HasSuffix(lower, "ий") {
+		stem := strings.TrimSuffix(base, "ii")
+		variations = append(variations, stem+"y", stem+"iy")
+	}
+
+	return removeDuplicates(variations)
+}
+
+// ToMRZ переводит имя, уже записанное латиницей, в формат MRZ: верхний регистр,
+// пробелы, дефисы и апострофы заменяются заполнителем '<'
+func ToMRZ(name string) string {
+	name = strings.ToUpper(strings.TrimSpace(name))
+
+	var sb strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			sb.WriteRune(r)
+		case r == ' ' || r == '-' || r == '\'':
+			sb.WriteByte('<')
+		}
+	}
+
+	return sb.String()
+}
+
+// FromMRZ переводит фрагмент MRZ в обычную запись: заполнители '<' заменяются пробелами
+func FromMRZ(field string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(field, "<", " ")), " ")
+}
+
+// MRZNameField формирует поле имени MRZ "ФАМИЛИЯ<<ИМЕНА" длиной length символов.
+// Кириллические фамилия и имена транслитерируются по ICAO Doc 9303. Слишком длинное поле
+// усекается (последний символ при этом - буква, что по Doc 9303 указывает на усечение),
+// короткое дополняется заполнителем
+func MRZNameField(surname, givenNames string, length int) string {
+	if IsCyrillic(surname) {
+		surname = TranslitICAO(surname)
+	}
+	if IsCyrillic(givenNames) {
+		givenNames = TranslitICAO(givenNames)
+	}
+
+	field := ToMRZ(surname)
+	if given := ToMRZ(givenNames); given != "" {
+		field += "<<" + given
+	}
+
+	if len(field) > length {
+		field = field[:length]
+	}
+
+	return field + strings.Repeat("<", length-len(field))
+}
+
+// ParseMRZNameField разбирает поле имени MRZ на фамилию и имена.
+// Признак truncated означает, что поле заполнено до конца и могло быть усечено
+func ParseMRZNameField(field string) (surname, givenNames string, truncated bool) {
+	truncated = len(field) > 0 && field[len(field)-1] != '<'
+
+	parts := strings.SplitN(field, "<<", 2)
+	surname = FromMRZ(parts[0])
+	if len(parts) > 1 {
+		givenNames = FromMRZ(parts[1])
+	}
+
+	return surname, givenNames, truncated
+}
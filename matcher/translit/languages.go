@@ -0,0 +1,296 @@
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Коды языков для подсказки языка и результата DetectLanguage
+const (
+	LanguageRussian    = "ru"
+	LanguageUkrainian  = "uk"
+	LanguageBelarusian = "be"
+	LanguageKazakh     = "kk"
+	LanguageSerbian    = "sr"
+	LanguageBulgarian  = "bg"
+)
+
+// languageStandards стандарты транслитерации, соответствующие языкам.
+// Для русского языка используются стандарты по умолчанию
+var languageStandards = map[string]string{
+	LanguageUkrainian:  "ukrainian",
+	LanguageBelarusian: "belarusian",
+	LanguageKazakh:     "kazakh",
+	LanguageSerbian:    "serbian",
+	LanguageBulgarian:  "bulgarian",
+}
+
+// LanguageStandard возвращает имя стандарта транслитерации для языка
+// или пустую строку, если для языка используются стандарты по умолчанию
+func LanguageStandard(language string) string {
+	return languageStandards[strings.ToLower(language)]
+}
+
+// IsDocumentStandard проверяет, пишутся ли по стандарту имена в паспортах: ICAO Doc 9303
+// для загранпаспортов или национальный стандарт языка ("IURII", "Zhaksylykov")
+func IsDocumentStandard(name string) bool {
+	if name == "icao" {
+		return true
+	}
+	for _, standard := range languageStandards {
+		if standard == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsKnownLanguage проверяет, поддерживается ли код языка. Пустой код допустим
+// и означает определение языка по буквам
+func IsKnownLanguage(language string) bool {
+	language = strings.ToLower(language)
+	return language == "" || language == LanguageRussian || languageStandards[language] != ""
+}
+
+// DetectLanguage определяет язык кириллического текста по характерным буквам.
+// Возвращает пустую строку, если текст не на кириллице или язык определить нельзя
+// (например, для "Иван Петров", одинакового в русском и болгарском)
+func DetectLanguage(text string) string {
+	if !IsCyrillic(text) {
+		return ""
+	}
+
+	lower := strings.ToLower(text)
+	switch {
+	case strings.ContainsAny(lower, "ђјљњћџ"):
+		return LanguageSerbian
+	case strings.ContainsAny(lower, "әғқңөұүһ"):
+		return LanguageKazakh
+	case strings.ContainsRune(lower, 'ў'):
+		return LanguageBelarusian
+	case strings.ContainsAny(lower, "їєґ"):
+		return LanguageUkrainian
+	case strings.ContainsRune(lower, 'і'):
+		// І есть и в белорусском, который в отличие от украинского сохраняет ы и э
+		if strings.ContainsAny(lower, "ыэ") {
+			return LanguageBelarusian
+		}
+		return LanguageUkrainian
+	case hasBulgarianHardSign(lower):
+		return LanguageBulgarian
+	case strings.ContainsAny(lower, "ыэёъ"):
+		return LanguageRussian
+	default:
+		return ""
+	}
+}
+
+// hasBulgarianHardSign проверяет, используется ли ъ как гласная (перед согласной или в конце слова),
+// что характерно для болгарского; в русском ъ стоит только перед е, ё, ю, я
+func hasBulgarianHardSign(lower string) bool {
+	runes := []rune(lower)
+	for i, r := range runes {
+		if r != 'ъ' {
+			continue
+		}
+		if i+1 == len(runes) || !strings.ContainsRune("еёюя", runes[i+1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// TranslitBelarusian транслитерация белорусских имен в написании, принятом в паспортах
+// ("Аляксандр" - "aliaksandr"): г - h, ў - u, я/ю/е после согласных - ia/iu/ie
+func TranslitBelarusian(text string) string {
+	// Приводим к нижнему регистру
+	text = strings.ToLower(text)
+
+	mapping := map[rune]string{
+		'а': "a", 'б': "b", 'в': "v", 'г': "h", 'ґ': "g", 'д': "d",
+		'е': "e", 'ё': "io", 'ж': "zh", 'з': "z", 'і': "i", 'и': "i",
+		'й': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+		'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ў': "u",
+		'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+		'ы': "y", 'ь': "", 'э': "e", 'ю': "iu", 'я': "ia",
+		'\'': "", '’': "", 'ʼ': "", 'ъ': "",
+	}
+
+	// В начале слова и после гласных, ў, ь и апострофа йотированные передаются через y
+	iotated := map[rune]string{'е': "ye", 'ё': "yo", 'ю': "yu", 'я': "ya"}
+
+	return transliterateIotated(text, mapping, iotated, "аеёіиоуўыэюяь'’ʼ")
+}
+
+// GetBelarusianVariations возвращает возможные вариации транслитерации белорусских имен
+func GetBelarusianVariations(text string) []string {
+	if !IsCyrillic(text) {
+		return []string{text}
+	}
+
+	lower := strings.ToLower(text)
+	base := TranslitBelarusian(text)
+	variations := []string{base}
+
+	// BGN/PCGN: ў - w, я/ю - ya/yu во всех позициях
+	bgn := strings.NewReplacer("ia", "ya", "iu", "yu", "io", "yo")
+	variations = append(variations, bgn.Replace(base))
+	if strings.ContainsRune(lower, 'ў') {
+		variations = append(variations, TranslitBelarusian(strings.ReplaceAll(lower, "ў", "в")))
+	}
+
+	// Русифицированная передача г как g
+	if strings.ContainsRune(lower, 'г') {
+		variations = append(variations, TranslitBelarusian(strings.ReplaceAll(lower, "г", "ґ")))
+	}
+
+	return removeDuplicates(variations)
+}
+
+// TranslitKazakh транслитерация казахских имен в написании, принятом в паспортах
+// ("Жақсылықов" - "zhaksylykov"): специфические буквы передаются ближайшими латинскими
+func TranslitKazakh(text string) string {
+	// Приводим к нижнему регистру
+	text = strings.ToLower(text)
+
+	mapping := map[rune]string{
+		'а': "a", 'ә': "a", 'б': "b", 'в': "v", 'г': "g", 'ғ': "g",
+		'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z", 'и': "i",
+		'й': "i", 'к': "k", 'қ': "k", 'л': "l", 'м': "m", 'н': "n",
+		'ң': "n", 'о': "o", 'ө': "o", 'п': "p", 'р': "r", 'с': "s",
+		'т': "t", 'у': "u", 'ұ': "u", 'ү': "u", 'ф': "f", 'х': "kh",
+		'һ': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "",
+		'ы': "y", 'і': "i", 'ь': "", 'э': "e", 'ю': "iu", 'я': "ia",
+	}
+
+	return transliterate(text, mapping)
+}
+
+// GetKazakhVariations возвращает возможные вариации транслитерации казахских имен
+func GetKazakhVariations(text string) []string {
+	if !IsCyrillic(text) {
+		return []string{text}
+	}
+
+	lower := strings.ToLower(text)
+	base := TranslitKazakh(text)
+	variations := []string{base}
+
+	// Написание с y для й, ю, я ("Yerlan", "Yuldash")
+	variations = append(variations, strings.NewReplacer("iu", "yu", "ia", "ya").Replace(base))
+
+	// Латиница 2021 года и распространенные написания: қ - q, ғ - gh, ә - ae
+	if strings.ContainsAny(lower, "қғә") {
+		latin := transliterate(lower, map[rune]string{'қ': "q", 'ғ': "gh", 'ә': "ae"})
+		variations = append(variations, TranslitKazakh(latin))
+	}
+
+	return removeDuplicates(variations)
+}
+
+// TranslitSerbian транслитерация по сербской латинице (ђ - đ, ј - j, љ - lj, њ - nj, ћ - ć, џ - dž)
+func TranslitSerbian(text string) string {
+	// Приводим к нижнему регистру
+	text = strings.ToLower(text)
+
+	mapping := map[rune]string{
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'ђ': "đ",
+		'е': "e", 'ж': "ž", 'з': "z", 'и': "i", 'ј': "j", 'к': "k",
+		'л': "l", 'љ': "lj", 'м': "m", 'н': "n", 'њ': "nj", 'о': "o",
+		'п': "p", 'р': "r", 'с': "s", 'т': "t", 'ћ': "ć", 'у': "u",
+		'ф': "f", 'х': "h", 'ц': "c", 'ч': "č", 'џ': "dž", 'ш': "š",
+	}
+
+	return transliterate(text, mapping)
+}
+
+// GetSerbianVariations возвращает сербскую латиницу и ее написания без диакритики
+// ("Đoković" - "djokovic", "dokovic") и в английской передаче ("chachak")
+func GetSerbianVariations(text string) []string {
+	if !IsCyrillic(text) {
+		return []string{text}
+	}
+
+	base := TranslitSerbian(text)
+	variations := []string{
+		base,
+		strings.NewReplacer("đ", "dj", "ć", "c", "č", "c", "dž", "dz", "ž", "z", "š", "s").Replace(base),
+		strings.NewReplacer("đ", "d", "ć", "c", "č", "c", "dž", "dz", "ž", "z", "š", "s").Replace(base),
+		strings.NewReplacer("đ", "dj", "ć", "ch", "č", "ch", "dž", "j", "ž", "zh", "š", "sh").Replace(base),
+	}
+
+	return removeDuplicates(variations)
+}
+
+// TranslitBulgarian транслитерация по болгарской системе 2009 года:
+// щ - sht, ъ - a, ь - y, окончание -ия - ia
+func TranslitBulgarian(text string) string {
+	// Приводим к нижнему регистру
+	text = strings.ToLower(text)
+
+	mapping := map[rune]string{
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e",
+		'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l",
+		'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s",
+		'т': "t", 'у': "u", 'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch",
+		'ш': "sh", 'щ': "sht", 'ъ': "a", 'ь': "y", 'ю': "yu", 'я': "ya",
+	}
+
+	// Окончание -ия в конце слова передается как -ia ("Мария" - "maria")
+	words := strings.Fields(text)
+	for i, word := range words {
+		if strings.HasSuffix(word, "ия") {
+			words[i] = transliterate(strings.TrimSuffix(word, "ия"), mapping) + "ia"
+		} else {
+			words[i] = transliterate(word, mapping)
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// GetBulgarianVariations возвращает возможные вариации транслитерации болгарских имен
+func GetBulgarianVariations(text string) []string {
+	if !IsCyrillic(text) {
+		return []string{text}
+	}
+
+	lower := strings.ToLower(text)
+	base := TranslitBulgarian(text)
+	variations := []string{base}
+
+	// Старые написания: ъ - u, х - kh, -ия - iya
+	if strings.ContainsRune(lower, 'ъ') {
+		variations = append(variations, TranslitBulgarian(strings.ReplaceAll(lower, "ъ", "у")))
+	}
+	if strings.ContainsRune(lower, 'х') {
+		variations = append(variations, strings.ReplaceAll(base, "h", "kh"))
+	}
+	if strings.Contains(lower, "ия") {
+		variations = append(variations, strings.ReplaceAll(base, "ia", "iya"))
+	}
+
+	return removeDuplicates(variations)
+}
+
+// transliterateIotated транслитерирует текст, передавая йотированные гласные в начале слова
+// и после символов из afterIotated по таблице iotated
+func transliterateIotated(text string, mapping, iotated map[rune]string, afterIotated string) string {
+	runes := []rune(text)
+	var sb strings.Builder
+	for i, r := range runes {
+		if latin, ok := iotated[r]; ok {
+			if i == 0 || !unicode.IsLetter(runes[i-1]) && !isApostrophe(runes[i-1]) ||
+				strings.ContainsRune(afterIotated, runes[i-1]) {
+				sb.WriteString(latin)
+				continue
+			}
+		}
+		if latin, ok := mapping[r]; ok {
+			sb.WriteString(latin)
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
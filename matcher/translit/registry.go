@@ -0,0 +1,268 @@
+package translit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Standard стандарт транслитерации
+type Standard interface {
+	// Transliterate переводит текст с кириллицы на латиницу
+	Transliterate(text string) string
+	// Reverse переводит текст с латиницы на кириллицу
+	Reverse(text string) string
+	// Variations возвращает возможные варианты написания текста
+	Variations(text string) []string
+}
+
+// FuncStandard стандарт транслитерации из отдельных функций.
+// Незаданные функции заменяются: Reverse возвращает текст без изменений,
+// Variations - результат Transliterate
+type FuncStandard struct {
+	Forward  func(string) string
+	Backward func(string) string
+	Variants func(string) []string
+}
+
+// Transliterate переводит текст с кириллицы на латиницу
+func (s FuncStandard) Transliterate(text string) string {
+	return s.Forward(text)
+}
+
+// Reverse переводит текст с латиницы на кириллицу
+func (s FuncStandard) Reverse(text string) string {
+	if s.Backward == nil {
+		return text
+	}
+	return s.Backward(text)
+}
+
+// Variations возвращает возможные варианты написания текста
+func (s FuncStandard) Variations(text string) []string {
+	if s.Variants == nil {
+		return []string{s.Transliterate(text)}
+	}
+	return s.Variants(text)
+}
+
+// Реестр стандартов транслитерации
+var (
+	registry = map[string]Standard{
+		"gost":       FuncStandard{Forward: TranslitGOST, Backward: TranslitGOSTReverse, Variants: GetGOSTVariations},
+		"iso9":       FuncStandard{Forward: TranslitISO9, Backward: TranslitISO9Reverse, Variants: GetISO9Variations},
+		"bgnpcgn":    FuncStandard{Forward: TranslitBGNPCGN, Backward: TranslitBGNPCGNReverse, Variants: GetBGNPCGNVariations},
+		"ungegn":     FuncStandard{Forward: TranslitUNGEGN, Backward: TranslitUNGEGNReverse, Variants: GetUNGEGNVariations},
+		"icao":       FuncStandard{Forward: TranslitICAO, Backward: TranslitICAOReverse, Variants: GetICAOVariations},
+		"belarusian": FuncStandard{Forward: TranslitBelarusian, Variants: GetBelarusianVariations},
+		"kazakh":     FuncStandard{Forward: TranslitKazakh, Variants: GetKazakhVariations},
+		"serbian":    FuncStandard{Forward: TranslitSerbian, Variants: GetSerbianVariations},
+		"bulgarian":  FuncStandard{Forward: TranslitBulgarian, Variants: GetBulgarianVariations},
+		"ukrainian":  FuncStandard{Forward: TranslitUkrainian, Backward: TranslitUkrainianReverse, Variants: GetUkrainianVariations},
+	}
+	registryMutex sync.RWMutex
+)
+
+// Register регистрирует стандарт транслитерации под указанным именем.
+// Стандарт с тем же именем, в том числе встроенный, заменяется
+func Register(name string, std Standard) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	registry[name] = std
+}
+
+// Lookup возвращает стандарт транслитерации по имени
+func Lookup(name string) (Standard, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	std, ok := registry[name]
+	return std, ok
+}
+
+// Standards возвращает отсортированный список имен зарегистрированных стандартов
+func Standards() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// TableStandard стандарт транслитерации, заданный таблицей соответствий.
+// Ключи таблиц - буквы или буквосочетания в нижнем регистре; при транслитерации
+// выбирается самое длинное совпадающее сочетание
+type TableStandard struct {
+	Name         string              `json:"name"`
+	Mapping      map[string]string   `json:"mapping"`                // Кириллица -> латиница
+	ReverseTable map[string]string   `json:"reverse,omitempty"`      // Латиница -> кириллица, по умолчанию обращение Mapping
+	Alternatives map[string][]string `json:"alternatives,omitempty"` // Альтернативные латинские написания букв для Variations
+}
+
+// Transliterate переводит текст с кириллицы на латиницу по таблице
+func (s *TableStandard) Transliterate(text string) string {
+	return replaceLongest(strings.ToLower(text), s.Mapping)
+}
+
+// Reverse переводит текст с латиницы на кириллицу по обратной таблице
+func (s *TableStandard) Reverse(text string) string {
+	return replaceLongest(strings.ToLower(text), s.ReverseTable)
+}
+
+// Variations возвращает транслитерацию текста и варианты с альтернативными
+// написаниями букв. Для текста на латинице возвращается обратная транслитерация
+func (s *TableStandard) Variations(text string) []string {
+	if !IsCyrillic(text) {
+		return []string{s.Reverse(text)}
+	}
+
+	variations := []string{s.Transliterate(text)}
+	lower := strings.ToLower(text)
+
+	// Для каждой буквы с альтернативами строим вариант, в котором она записана иначе
+	letters := make([]string, 0, len(s.Alternatives))
+	for letter := range s.Alternatives {
+		letters = append(letters, letter)
+	}
+	sort.Strings(letters)
+
+	for _, letter := range letters {
+		if !strings.Contains(lower, letter) {
+			continue
+		}
+		for _, alternative := range s.Alternatives[letter] {
+			mapping := make(map[string]string, len(s.Mapping))
+			for k, v := range s.Mapping {
+				mapping[k] = v
+			}
+			mapping[letter] = alternative
+			variations = append(variations, replaceLongest(lower, mapping))
+		}
+	}
+
+	return removeDuplicates(variations)
+}
+
+// LoadTable загружает стандарт транслитерации из таблицы соответствий в формате JSON:
+//
+//	{"name": "bank", "mapping": {"а": "a", "щ": "shch"}, "alternatives": {"й": ["i", "j"]}}
+//
+// Если обратная таблица "reverse" не задана, она строится обращением "mapping"
+func LoadTable(r io.Reader) (*TableStandard, error) {
+	var std TableStandard
+	if err := json.NewDecoder(r).Decode(&std); err != nil {
+		return nil, err
+	}
+
+	if std.Name == "" {
+		return nil, errors.New("translit table: name is required")
+	}
+	if len(std.Mapping) == 0 {
+		return nil, fmt.Errorf("translit table %q: mapping is empty", std.Name)
+	}
+
+	std.Mapping = lowerKeys(std.Mapping)
+	if len(std.ReverseTable) == 0 {
+		std.ReverseTable = reverseMapping(std.Mapping)
+	} else {
+		std.ReverseTable = lowerKeys(std.ReverseTable)
+	}
+
+	return &std, nil
+}
+
+// RegisterTableFile загружает таблицу транслитерации из файла и регистрирует
+// стандарт под указанным в ней именем
+func RegisterTableFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	std, err := LoadTable(file)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+
+	Register(std.Name, std)
+	return std.Name, nil
+}
+
+// reverseMapping строит обратную таблицу. Если одному латинскому написанию
+// соответствует несколько кириллических, выбирается первое по алфавиту
+func reverseMapping(mapping map[string]string) map[string]string {
+	keys := make([]string, 0, len(mapping))
+	for k := range mapping {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	reverse := make(map[string]string, len(mapping))
+	for _, k := range keys {
+		latin := mapping[k]
+		if latin == "" {
+			continue
+		}
+		if _, ok := reverse[latin]; !ok {
+			reverse[latin] = k
+		}
+	}
+
+	return reverse
+}
+
+// lowerKeys приводит ключи таблицы к нижнему регистру
+func lowerKeys(table map[string]string) map[string]string {
+	result := make(map[string]string, len(table))
+	for k, v := range table {
+		result[strings.ToLower(k)] = v
+	}
+	return result
+}
+
+// replaceLongest заменяет в тексте сочетания из таблицы, выбирая на каждой позиции
+// самое длинное совпадение. Символы без соответствия сохраняются
+func replaceLongest(text string, table map[string]string) string {
+	maxLen := 0
+	for k := range table {
+		if n := utf8.RuneCountInString(k); n > maxLen {
+			maxLen = n
+		}
+	}
+
+	runes := []rune(text)
+	var sb strings.Builder
+	for i := 0; i < len(runes); {
+		matched := false
+		for n := maxLen; n > 0; n-- {
+			if i+n > len(runes) {
+				continue
+			}
+			if replacement, ok := table[string(runes[i:i+n])]; ok {
+				sb.WriteString(replacement)
+				i += n
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			sb.WriteRune(runes[i])
+			i++
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,323 @@
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Алфавиты (письменности), различаемые DetectScripts
+const (
+	ScriptLatin      = "latin"
+	ScriptCyrillic   = "cyrillic"
+	ScriptGreek      = "greek"
+	ScriptArmenian   = "armenian"
+	ScriptGeorgian   = "georgian"
+	ScriptArabic     = "arabic"
+	ScriptHebrew     = "hebrew"
+	ScriptDevanagari = "devanagari"
+	ScriptHan        = "han"
+	ScriptHiragana   = "hiragana"
+	ScriptKatakana   = "katakana"
+	ScriptHangul     = "hangul"
+	ScriptOther      = "other"
+)
+
+// scriptTables таблицы Unicode для определения алфавита буквы
+var scriptTables = []struct {
+	name  string
+	table *unicode.RangeTable
+}{
+	{ScriptCyrillic, unicode.Cyrillic},
+	{ScriptLatin, unicode.Latin},
+	{ScriptGreek, unicode.Greek},
+	{ScriptArmenian, unicode.Armenian},
+	{ScriptGeorgian, unicode.Georgian},
+	{ScriptArabic, unicode.Arabic},
+	{ScriptHebrew, unicode.Hebrew},
+	{ScriptDevanagari, unicode.Devanagari},
+	{ScriptHan, unicode.Han},
+	{ScriptHiragana, unicode.Hiragana},
+	{ScriptKatakana, unicode.Katakana},
+	{ScriptHangul, unicode.Hangul},
+}
+
+// latinHomoglyphs латинские буквы, совпадающие по начертанию с кириллическими
+var latinHomoglyphs = map[rune]rune{
+	'a': 'а', 'c': 'с', 'e': 'е', 'i': 'і', 'j': 'ј', 'o': 'о', 'p': 'р',
+	's': 'ѕ', 'x': 'х', 'y': 'у',
+	'A': 'А', 'B': 'В', 'C': 'С', 'E': 'Е', 'H': 'Н', 'I': 'І', 'J': 'Ј',
+	'K': 'К', 'M': 'М', 'O': 'О', 'P': 'Р', 'S': 'Ѕ', 'T': 'Т', 'X': 'Х',
+	'Y': 'У',
+}
+
+// cyrillicHomoglyphs кириллические буквы, совпадающие по начертанию с латинскими
+var cyrillicHomoglyphs = func() map[rune]rune {
+	reversed := make(map[rune]rune, len(latinHomoglyphs))
+	for latin, cyrillic := range latinHomoglyphs {
+		reversed[cyrillic] = latin
+	}
+	return reversed
+}()
+
+// ScriptInfo сведения об алфавитах, которыми написано имя
+type ScriptInfo struct {
+	// Dominant алфавит большинства букв (пустая строка, если букв нет)
+	Dominant string `json:"dominant"`
+	// Scripts алфавиты слов в порядке появления; алфавит слова - алфавит большинства его букв
+	Scripts []string `json:"scripts"`
+	// Letters количество букв каждого алфавита
+	Letters map[string]int `json:"letters"`
+	// MixedTokens слова, в которых встречаются буквы разных алфавитов
+	MixedTokens []string `json:"mixed_tokens,omitempty"`
+	// HomoglyphSuspected в смешанном слове буквы другого алфавита совпадают по начертанию
+	// с буквами алфавита слова ("Ивaнов" с латинской "a")
+	HomoglyphSuspected bool `json:"homoglyph_suspected"`
+}
+
+// MixedScript проверяет, встречаются ли в имени буквы разных алфавитов
+func (s ScriptInfo) MixedScript() bool {
+	return len(s.Letters) > 1
+}
+
+// ScriptOf возвращает алфавит буквы или пустую строку для символов, не являющихся буквами
+func ScriptOf(r rune) string {
+	if !unicode.IsLetter(r) {
+		return ""
+	}
+	for _, script := range scriptTables {
+		if unicode.Is(script.table, r) {
+			return script.name
+		}
+	}
+	return ScriptOther
+}
+
+// DetectScripts определяет алфавиты, которыми написано имя: преобладающий алфавит,
+// алфавиты отдельных слов, слова со смешением алфавитов и подозрение на буквы-двойники
+func DetectScripts(name string) ScriptInfo {
+	info := ScriptInfo{Letters: make(map[string]int)}
+
+	// Слова разделяются любыми символами, кроме букв и диакритических знаков
+	tokens := strings.FieldsFunc(name, func(r rune) bool { return !isTokenRune(r) })
+
+	var order []string
+	for _, token := range tokens {
+		counts := make(map[string]int)
+		var tokenOrder []string
+		for _, r := range token {
+			script := ScriptOf(r)
+			if script == "" {
+				continue
+			}
+			if counts[script] == 0 {
+				tokenOrder = append(tokenOrder, script)
+			}
+			if info.Letters[script] == 0 {
+				order = append(order, script)
+			}
+			counts[script]++
+			info.Letters[script]++
+		}
+		if len(tokenOrder) == 0 {
+			continue
+		}
+
+		tokenScript := dominantScript(counts, tokenOrder)
+		if !containsScript(info.Scripts, tokenScript) {
+			info.Scripts = append(info.Scripts, tokenScript)
+		}
+
+		if len(tokenOrder) > 1 {
+			info.MixedTokens = append(info.MixedTokens, token)
+			if isHomoglyphToken(token, tokenScript) {
+				info.HomoglyphSuspected = true
+			}
+		}
+	}
+
+	info.Dominant = dominantScript(info.Letters, order)
+	return info
+}
+
+// SameScripts проверяет, написаны ли строки словами одних и тех же алфавитов.
+// Строки без букв считаются написанными тем же алфавитом, что и любая другая строка
+func SameScripts(s1, s2 string) bool {
+	return DetectScripts(s1).SameScripts(DetectScripts(s2))
+}
+
+// SameScripts проверяет, совпадают ли наборы алфавитов слов
+func (s ScriptInfo) SameScripts(other ScriptInfo) bool {
+	if len(s.Scripts) == 0 || len(other.Scripts) == 0 {
+		return true
+	}
+	if len(s.Scripts) != len(other.Scripts) {
+		return false
+	}
+	for _, script := range s.Scripts {
+		if !containsScript(other.Scripts, script) {
+			return false
+		}
+	}
+	return true
+}
+
+// Share возвращает долю букв алфавита среди всех букв имени
+func (s ScriptInfo) Share(script string) float64 {
+	total := 0
+	for _, count := range s.Letters {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Letters[script]) / float64(total)
+}
+
+// dominantScript возвращает алфавит с наибольшим числом букв; при равенстве - встретившийся раньше
+func dominantScript(counts map[string]int, order []string) string {
+	dominant := ""
+	for _, script := range order {
+		if dominant == "" || counts[script] > counts[dominant] {
+			dominant = script
+		}
+	}
+	return dominant
+}
+
+// NormalizeHomoglyphs переписывает слова с буквами-двойниками другого алфавита
+// ("Ивaнов" с латинской "a") буквами алфавита слова. Возвращает строку и признак замены.
+// Смешанные слова с буквами, не имеющими двойника, не изменяются
+func NormalizeHomoglyphs(name string) (string, bool) {
+	runes := []rune(name)
+	changed := false
+
+	for i := 0; i < len(runes); {
+		if !isTokenRune(runes[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && isTokenRune(runes[j]) {
+			j++
+		}
+		if normalizeHomoglyphToken(runes[i:j]) {
+			changed = true
+		}
+		i = j
+	}
+
+	if !changed {
+		return name, false
+	}
+	return string(runes), true
+}
+
+// normalizeHomoglyphToken заменяет в слове буквы-двойники на буквы преобладающего алфавита слова
+func normalizeHomoglyphToken(token []rune) bool {
+	counts := make(map[string]int)
+	var order []string
+	for _, r := range token {
+		script := ScriptOf(r)
+		if script == "" {
+			continue
+		}
+		if counts[script] == 0 {
+			order = append(order, script)
+		}
+		counts[script]++
+	}
+	if len(order) < 2 {
+		return false
+	}
+
+	script := dominantScript(counts, order)
+	if !isHomoglyphToken(string(token), script) {
+		return false
+	}
+
+	homoglyphs := homoglyphTable(script)
+	for i, r := range token {
+		if replacement, ok := homoglyphs[r]; ok {
+			token[i] = replacement
+		}
+	}
+	return true
+}
+
+// isTokenRune проверяет, является ли символ частью слова (буква или диакритический знак)
+func isTokenRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.Is(unicode.Mn, r)
+}
+
+// homoglyphTable возвращает таблицу замены букв-двойников на буквы алфавита script
+func homoglyphTable(script string) map[rune]rune {
+	switch script {
+	case ScriptCyrillic:
+		return latinHomoglyphs
+	case ScriptLatin:
+		return cyrillicHomoglyphs
+	default:
+		return nil
+	}
+}
+
+// isHomoglyphToken проверяет, что все буквы слова не из его алфавита имеют двойника в этом алфавите
+func isHomoglyphToken(token, script string) bool {
+	homoglyphs := homoglyphTable(script)
+	if homoglyphs == nil {
+		return false
+	}
+
+	for _, r := range token {
+		letterScript := ScriptOf(r)
+		if letterScript == "" || letterScript == script {
+			continue
+		}
+		if _, ok := homoglyphs[r]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// containsScript проверяет наличие алфавита в списке
+func containsScript(scripts []string, script string) bool {
+	for _, s := range scripts {
+		if s == script {
+			return true
+		}
+	}
+	return false
+}
+
+// latinFolding замена латинских букв с диакритикой на базовые
+var latinFolding = map[rune]string{
+	'á': "a", 'à': "a", 'â': "a", 'ä': "a", 'ã': "a", 'å': "a", 'ą': "a", 'ă': "a",
+	'ć': "c", 'č': "c", 'ç': "c",
+	'ď': "d", 'đ': "d",
+	'é': "e", 'è': "e", 'ê': "e", 'ë': "e", 'ě': "e", 'ę': "e",
+	'í': "i", 'ì': "i", 'î': "i", 'ï': "i",
+	'ł': "l", 'ľ': "l",
+	'ń': "n", 'ň': "n", 'ñ': "n",
+	'ó': "o", 'ò': "o", 'ô': "o", 'ö': "o", 'õ': "o", 'ø': "o", 'ő': "o",
+	'ř': "r", 'ŕ': "r",
+	'ś': "s", 'š': "s", 'ş': "s", 'ș': "s", 'ß': "ss",
+	'ť': "t", 'ţ': "t", 'ț': "t",
+	'ú': "u", 'ù': "u", 'û': "u", 'ü': "u", 'ů': "u", 'ű': "u",
+	'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// FoldDiacritics заменяет латинские буквы с диакритикой базовыми ("José" - "Jose", "Paweł" - "Pawel").
+// Регистр заглавных букв с диакритикой не сохраняется
+func FoldDiacritics(text string) string {
+	var sb strings.Builder
+	for _, r := range text {
+		if folded, ok := latinFolding[unicode.ToLower(r)]; ok {
+			sb.WriteString(folded)
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
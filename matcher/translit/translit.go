@@ -38,6 +38,12 @@ func GetAllTransliterations(name string) []string {
 		TranslitISO9(name),
 		TranslitBGNPCGN(name),
 		TranslitUNGEGN(name),
+		TranslitICAO(name),
+	}
+
+	// Для украинского текста добавляем официальную украинскую транслитерацию
+	if IsUkrainian(name) {
+		standardTransliterations = append(standardTransliterations, TranslitUkrainian(name))
 	}
 
 	// Добавляем исходное имя в список
@@ -49,6 +55,75 @@ func GetAllTransliterations(name string) []string {
 	return uniqueTransliterations
 }
 
+// StandardOriginal обозначает исходное написание имени без транслитерации
+const StandardOriginal = "original"
+
+// Variant вариант написания имени со стандартами транслитерации, которые его дают
+type Variant struct {
+	Text      string   `json:"text"`
+	Standards []string `json:"standards"`
+}
+
+// DefaultStandards стандарты транслитерации, используемые по умолчанию
+var DefaultStandards = []string{"gost", "iso9", "bgnpcgn", "ungegn", "icao"}
+
+// GetAllTransliterationVariants возвращает варианты написания имени по стандартам
+// DefaultStandards с указанием стандартов
+func GetAllTransliterationVariants(name string) []Variant {
+	return GetTransliterationVariants(name, DefaultStandards)
+}
+
+// GetTransliterationVariants возвращает исходное написание имени и его транслитерации
+// по указанным стандартам. Одинаковые варианты разных стандартов объединяются в один,
+// неизвестные стандарты пропускаются
+func GetTransliterationVariants(name string, standards []string) []Variant {
+	variants := []Variant{{Text: name, Standards: []string{StandardOriginal}}}
+
+	// Имя не на кириллице не транслитерируется
+	if !IsCyrillic(name) {
+		return variants
+	}
+
+	for _, standard := range standards {
+		fn, ok := LookupFunc(standard)
+		if !ok {
+			continue
+		}
+		variants = addVariant(variants, fn(name), standard)
+	}
+
+	return variants
+}
+
+// AddLanguageVariants дополняет варианты всеми вариациями стандарта, соответствующего языку
+// (см. LanguageStandard). Для русского и неизвестного языка варианты не меняются
+func AddLanguageVariants(variants []Variant, name, language string) []Variant {
+	if !IsCyrillic(name) {
+		return variants
+	}
+
+	std, ok := Lookup(LanguageStandard(language))
+	if !ok {
+		return variants
+	}
+	for _, text := range std.Variations(name) {
+		variants = addVariant(variants, text, LanguageStandard(language))
+	}
+
+	return variants
+}
+
+// addVariant добавляет вариант написания или дописывает стандарт к уже существующему
+func addVariant(variants []Variant, text, standard string) []Variant {
+	for i := range variants {
+		if variants[i].Text == text {
+			variants[i].Standards = append(variants[i].Standards, standard)
+			return variants
+		}
+	}
+	return append(variants, Variant{Text: text, Standards: []string{standard}})
+}
+
 // removeDuplicates удаляет дубликаты из слайса строк, сохраняя порядок
 func removeDuplicates(elements []string) []string {
 	seen := make(map[string]bool)
@@ -64,46 +139,26 @@ func removeDuplicates(elements []string) []string {
 
 // GetTranslitFunction возвращает функцию транслитерации по имени стандарта
 func GetTranslitFunction(standard string) func(string) string {
-	switch standard {
-	case "iso9":
-		return TranslitISO9
-	case "gost":
-		return TranslitGOST
-	case "bgnpcgn":
-		return TranslitBGNPCGN
-	case "ungegn":
-		return TranslitUNGEGN
-	case "ukrainian":
-		return TranslitUkrainian
-	default:
-		return TranslitISO9 // По умолчанию ISO 9
+	if fn, ok := LookupFunc(standard); ok {
+		return fn
 	}
+	return TranslitISO9 // По умолчанию ISO 9
 }
 
-// TranslitUkrainian транслитерация по украинскому стандарту
-func TranslitUkrainian(text string) string {
-	// Приводим к нижнему регистру
-	text = strings.ToLower(text)
-
-	mapping := map[rune]string{
-		// Общие с русским буквы
-		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e",
-		'ж': "zh", 'з': "z", 'и': "y", 'к': "k", 'л': "l", 'м': "m",
-		'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t",
-		'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh",
-		'щ': "shch", 'ь': "", 'ю': "yu", 'я': "ya",
+// RegisterFunc регистрирует функцию транслитерации под именем стандарта.
+// Зарегистрированная функция заменяет встроенный стандарт с тем же именем
+func RegisterFunc(name string, fn func(string) string) {
+	Register(name, FuncStandard{Forward: fn})
+}
 
-		// Специфические украинские буквы
-		'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+// LookupFunc возвращает функцию транслитерации по имени стандарта.
+// Для неизвестного стандарта возвращает false
+func LookupFunc(standard string) (func(string) string, bool) {
+	std, ok := Lookup(standard)
+	if !ok {
+		return nil, false
 	}
-
-	mapping['й'] = "y" // Изменено на "y" для соответствия украинской транслитерации
-
-	// Специальные правила для украинской транслитерации
-	// Обработка 'зг' как 'zgh'
-	text = strings.ReplaceAll(text, "зг", "zgh")
-
-	return transliterate(text, mapping)
+	return std.Transliterate, true
 }
 
 // transliterate вспомогательная функция для транслитерации
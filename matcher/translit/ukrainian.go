@@ -0,0 +1,157 @@
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+// IsUkrainian проверяет, написан ли текст по-украински (см. DetectLanguage)
+func IsUkrainian(text string) bool {
+	return DetectLanguage(text) == LanguageUkrainian
+}
+
+// TranslitUkrainian транслитерация по украинскому стандарту (постановление КМУ № 55 от 27.01.2010).
+// Буквы є, ї, й, ю, я в начале слова передаются как ye, yi, y, yu, ya, в остальных позициях -
+// как ie, i, i, iu, ia; сочетание зг передается как zgh, мягкий знак и апостроф не передаются
+func TranslitUkrainian(text string) string {
+	// Приводим к нижнему регистру
+	text = strings.ToLower(text)
+
+	mapping := map[rune]string{
+		'а': "a", 'б': "b", 'в': "v", 'г': "h", 'ґ': "g", 'д': "d",
+		'е': "e", 'є': "ie", 'ж': "zh", 'з': "z", 'и': "y", 'і': "i",
+		'ї': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n",
+		'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+		'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+		'ю': "iu", 'я': "ia", 'ь': "", '\'': "", '’': "", 'ʼ': "",
+
+		// Русские буквы на случай смешанного текста
+		'ы': "y", 'э': "e", 'ё': "io", 'ъ': "",
+	}
+
+	// Написание в начале слова
+	initial := map[rune]string{
+		'є': "ye", 'ї': "yi", 'й': "y", 'ю': "yu", 'я': "ya",
+	}
+
+	runes := []rune(text)
+	var sb strings.Builder
+	for i, r := range runes {
+		atStart := i == 0 || (!unicode.IsLetter(runes[i-1]) && !isApostrophe(runes[i-1]))
+
+		switch {
+		case atStart && initial[r] != "":
+			sb.WriteString(initial[r])
+		case r == 'г' && i > 0 && runes[i-1] == 'з':
+			sb.WriteString("gh") // зг - zgh, чтобы отличать от жь - zh
+		default:
+			if latin, ok := mapping[r]; ok {
+				sb.WriteString(latin)
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// TranslitUkrainianReverse обратная транслитерация с латиницы на украинскую кириллицу.
+// Учитывает написание є, ї, ю, я в начале слова (ye, yi, yu, ya) и в остальных позициях (ie, i, iu, ia)
+func TranslitUkrainianReverse(text string) string {
+	// Приводим к нижнему регистру
+	runes := []rune(strings.ToLower(text))
+
+	// Сочетания в начале слова и в остальных позициях, от длинных к коротким
+	initial := []struct{ latin, cyrillic string }{
+		{"ye", "є"}, {"yi", "ї"}, {"yu", "ю"}, {"ya", "я"},
+	}
+	common := []struct{ latin, cyrillic string }{
+		{"shch", "щ"}, {"zgh", "зг"},
+		{"zh", "ж"}, {"kh", "х"}, {"ts", "ц"}, {"ch", "ч"}, {"sh", "ш"},
+		{"iu", "ю"}, {"ia", "я"}, {"ie", "є"},
+	}
+	mapping := map[rune]string{
+		'a': "а", 'b': "б", 'v': "в", 'h': "г", 'g': "ґ", 'd': "д",
+		'e': "е", 'z': "з", 'y': "и", 'i': "і", 'k': "к", 'l': "л",
+		'm': "м", 'n': "н", 'o': "о", 'p': "п", 'r': "р", 's': "с",
+		't': "т", 'u': "у", 'f': "ф",
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(runes); {
+		atStart := i == 0 || !unicode.IsLetter(runes[i-1])
+		rest := string(runes[i:])
+
+		// Окончания "ii" и "yi" соответствуют "ій" и "ий" ("Oleksii" - "Олексій", "Hurskyi" - "Гурський")
+		if !atStart && i+2 <= len(runes) && (i+2 == len(runes) || !unicode.IsLetter(runes[i+2])) {
+			if strings.HasPrefix(rest, "ii") {
+				sb.WriteString("ій")
+				i += 2
+				continue
+			}
+			if strings.HasPrefix(rest, "yi") {
+				sb.WriteString("ий")
+				i += 2
+				continue
+			}
+		}
+
+		matched := false
+		candidates := common
+		if atStart {
+			candidates = append(append([]struct{ latin, cyrillic string }{}, initial...), common...)
+		}
+		for _, c := range candidates {
+			if strings.HasPrefix(rest, c.latin) {
+				sb.WriteString(c.cyrillic)
+				i += len([]rune(c.latin))
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		if cyrillic, ok := mapping[runes[i]]; ok {
+			sb.WriteString(cyrillic)
+		} else {
+			sb.WriteRune(runes[i])
+		}
+		i++
+	}
+
+	return sb.String()
+}
+
+// GetUkrainianVariations возвращает возможные вариации транслитерации по украинскому стандарту
+func GetUkrainianVariations(text string) []string {
+	if !IsCyrillic(text) {
+		// Если текст не на кириллице, пытаемся выполнить обратную транслитерацию
+		return []string{TranslitUkrainianReverse(text)}
+	}
+
+	base := TranslitUkrainian(text)
+	variations := []string{base}
+
+	lower := strings.ToLower(text)
+
+	// Неофициальная передача г как g ("Olga" вместо "Olha")
+	if strings.ContainsRune(lower, 'г') {
+		variations = append(variations, TranslitUkrainian(strings.ReplaceAll(lower, "г", "ґ")))
+	}
+
+	// Окончание "ій" часто передается как "iy" или "y" ("Andriy")
+	if strings.HasSuffix(lower, "ій") {
+		stem := strings.TrimSuffix(base, "ii")
+		variations = append(variations, stem+"iy", stem+"y")
+	}
+
+	return removeDuplicates(variations)
+}
+
+// isApostrophe проверяет, является ли символ апострофом украинского письма
+func isApostrophe(r rune) bool {
+	return r == '\'' || r == '’' || r == 'ʼ'
+}
@@ -3,11 +3,17 @@ package matcher
 import (
 	"sync"
 	"time"
+
+	"github.com/x0rium/compareNames/matcher/translit"
 )
 
-// Attribute представляет дополнительный атрибут для сравнения
+// Attribute представляет дополнительный атрибут для сравнения.
+// Если заданы значения обеих сторон (Value1, Value2), совпадение вычисляется
+// компаратором из Config.AttributeRules, иначе используется готовый признак Match
 type Attribute struct {
-	Match bool `json:"match"`
+	Match  bool   `json:"match"`
+	Value1 string `json:"value1,omitempty"`
+	Value2 string `json:"value2,omitempty"`
 }
 
 // Attributes карта дополнительных атрибутов
@@ -15,27 +21,77 @@ type Attributes map[string]Attribute
 
 // NameMatcher основной тип для сравнения имен
 type NameMatcher struct {
-	Config          Config
-	nameVariantions map[string][]string // Кэш для хранения вариаций имен
-	cache           *Cache              // Кэш для хранения результатов сравнения
-	mutex           sync.RWMutex        // Мьютекс для потокобезопасности
+	Config           Config
+	nameVariantions  map[string][]string           // Кэш для хранения вариаций имен
+	translitVariants map[string][]translit.Variant // Кэш для хранения вариантов транслитерации
+	cache            *Cache                        // Кэш для хранения результатов сравнения
+	mutex            sync.RWMutex                  // Мьютекс для потокобезопасности
 }
 
-// MatchResult содержит результаты сравнения имен
+// MatchResult содержит результаты сравнения имен.
+// ExactMatch означает, что имена равны после предобработки utils.PreprocessName: без учета регистра,
+// дефисов, апострофов и лишних пробелов. Определение одинаково для обоих конвейеров сравнения
 type MatchResult struct {
-	ExactMatch                bool    `json:"exact_match"`
-	Score                     int     `json:"score"`
-	MatchType                 string  `json:"match_type"`
-	BestMatch1                string  `json:"best_match1,omitempty"`
-	BestMatch2                string  `json:"best_match2,omitempty"`
-	LevenshteinScore          float64 `json:"levenshtein_score,omitempty"`
-	JaroWinklerScore          float64 `json:"jaro_winkler_score,omitempty"`
-	PhoneticScore             float64 `json:"phonetic_score,omitempty"`
-	DoubleMetaphoneScore      float64 `json:"double_metaphone_score,omitempty"`
-	CosineScore               float64 `json:"cosine_score,omitempty"`
-	AdditionalAttributesScore float64 `json:"additional_attributes_score,omitempty"`
-	ProcessingTimeMS          int64   `json:"processing_time_ms"`
-	FromCache                 bool    `json:"from_cache,omitempty"`
+	ExactMatch                bool     `json:"exact_match"`
+	Score                     int      `json:"score"`
+	MatchType                 string   `json:"match_type"`
+	BestMatch1                string   `json:"best_match1,omitempty"`
+	BestMatch2                string   `json:"best_match2,omitempty"`
+	BestMatch1Standards       []string `json:"best_match1_standards,omitempty"` // Стандарты транслитерации варианта BestMatch1
+	BestMatch2Standards       []string `json:"best_match2_standards,omitempty"` // Стандарты транслитерации варианта BestMatch2
+	LevenshteinScore          float64  `json:"levenshtein_score,omitempty"`
+	JaroWinklerScore          float64  `json:"jaro_winkler_score,omitempty"`
+	PhoneticScore             float64  `json:"phonetic_score,omitempty"`
+	DoubleMetaphoneScore      float64  `json:"double_metaphone_score,omitempty"`
+	CosineScore               float64  `json:"cosine_score,omitempty"`
+	AdditionalAttributesScore float64  `json:"additional_attributes_score,omitempty"`
+	ProcessingTimeMS          int64    `json:"processing_time_ms"`
+	FromCache                 bool     `json:"from_cache,omitempty"`
+	HomoglyphsNormalized      bool     `json:"homoglyphs_normalized,omitempty"`  // В именах заменены буквы-двойники другого алфавита
+	MixedScriptSuspected      bool     `json:"mixed_script_suspected,omitempty"` // В имени смешаны алфавиты (возможная подмена символов)
+	SameFamily                bool     `json:"same_family,omitempty"`            // Фамилии - мужская и женская формы одной фамилии
+	GenderConflict            bool     `json:"gender_conflict,omitempty"`        // Имена указывают на разный пол
+	CognateScore              float64  `json:"cognate_score,omitempty"`          // Межъязыковое соответствие имен ("Пётр" - "Pierre")
+
+	Explanation *Explanation `json:"explanation,omitempty"` // Пояснение к оценке (кроме точных совпадений)
+}
+
+// Explanation пояснение к итоговой оценке сравнения
+type Explanation struct {
+	BaseScore         float64       `json:"base_score"`                   // Взвешенная оценка метрик до бонусов
+	Bonuses           []Bonus       `json:"bonuses,omitempty"`            // Сработавшие бонусы
+	TotalBonus        float64       `json:"total_bonus"`                  // Суммарный бонус после ограничения
+	BonusCapped       bool          `json:"bonus_capped"`                 // Суммарный бонус ограничен максимумом
+	AttributesApplied bool          `json:"attributes_applied,omitempty"` // В оценку вошли дополнительные атрибуты
+	GenderPenalty     float64       `json:"gender_penalty,omitempty"`     // Снижение оценки за разный пол
+	Substitution      *Substitution `json:"substitution,omitempty"`       // Оценка получена сравнением с заменой формы имени
+	ScoreClamped      bool          `json:"score_clamped"`                // Оценка ограничена значением 0.99
+	AlignedParts      []AlignedPart `json:"aligned_parts,omitempty"`      // Сопоставленные части имен
+}
+
+// Bonus бонус к базовой оценке
+type Bonus struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// AlignedPart пара сопоставленных частей имен. Для части без пары
+// соответствующее поле пустое
+type AlignedPart struct {
+	Role  string  `json:"role"`
+	Part1 string  `json:"part1"`
+	Part2 string  `json:"part2"`
+	Score float64 `json:"score"`
+}
+
+// Substitution замена формы имени, по которой получена оценка: имена сравнивались так,
+// будто Part2 записано формой Part1, и оценка сравнения умножена на Weight.
+// Метрики и сопоставленные части результата относятся к исходным именам
+type Substitution struct {
+	Kind   string  `json:"kind"` // Вид замены: SubstitutionCognate или SubstitutionNickname
+	Part1  string  `json:"part1"`
+	Part2  string  `json:"part2"`
+	Weight float64 `json:"weight"`
 }
 
 // NameMatchMetrics структура с метриками совпадения
@@ -63,12 +119,26 @@ type CacheItem struct {
 	CreateTime time.Time
 }
 
-// NewNameMatcher создает новый экземпляр NameMatcher
+// NewNameMatcher создает новый экземпляр NameMatcher.
+// Экземпляр безопасен для конкурентного использования и рассчитан на многократные
+// вызовы Match: кэш результатов и вариаций имен сохраняется между вызовами
 func NewNameMatcher(cfg *Config) *NameMatcher {
-	matcher := &NameMatcher{
-		nameVariantions: make(map[string][]string),
+	matcher := newNameMatcher(cfg)
+	matcher.nameVariantions = make(map[string][]string)
+	matcher.translitVariants = make(map[string][]translit.Variant)
+
+	// Инициализируем кэш, если включено кэширование
+	if matcher.Config.EnableCaching {
+		matcher.cache = NewCache(matcher.Config.MaxCacheSize, 15*time.Minute) // TTL 15 минут
 	}
 
+	return matcher
+}
+
+// newNameMatcher создает экземпляр NameMatcher без кэшей для разовых сравнений
+func newNameMatcher(cfg *Config) *NameMatcher {
+	matcher := &NameMatcher{}
+
 	// Используем конфигурацию по умолчанию, если не предоставлена
 	if cfg == nil {
 		matcher.Config = DefaultConfig()
@@ -86,11 +156,6 @@ func NewNameMatcher(cfg *Config) *NameMatcher {
 		}
 	}
 
-	// Инициализируем кэш, если включено кэширование
-	if matcher.Config.EnableCaching {
-		matcher.cache = NewCache(matcher.Config.MaxCacheSize, 15*time.Minute) // TTL 15 минут
-	}
-
 	return matcher
 }
 
@@ -2,7 +2,10 @@ package matcher
 
 import (
 	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/x0rium/compareNames/matcher/translit"
 )
 
 // PreprocessName предобработка имени: приведение к нижнему регистру, удаление лишних символов
@@ -15,6 +18,9 @@ func (m *NameMatcher) PreprocessName(name string) string {
 	// Приведение к нижнему регистру
 	name = strings.ToLower(name)
 
+	// Замена букв-двойников другого алфавита в словах ("ивaнов" с латинской "a")
+	name, _ = translit.NormalizeHomoglyphs(name)
+
 	// Преобразование дефисов в пробелы для корректной обработки двойных фамилий
 	name = strings.ReplaceAll(name, "-", " ")
 
@@ -33,8 +39,8 @@ func (m *NameMatcher) PreprocessName(name string) string {
 // 	return m.PreprocessName(name)
 // }
 
-// Проверяет, содержит ли имя инициалы
-func (m *NameMatcher) hasInitials(name string) bool {
+// HasInitials проверяет, содержит ли имя инициалы
+func (m *NameMatcher) HasInitials(name string) bool {
 	// Проверяем наличие точек в имени
 	if strings.Contains(name, ".") {
 		return true
@@ -50,8 +56,8 @@ func (m *NameMatcher) hasInitials(name string) bool {
 	return false
 }
 
-// Разбивает имя на части и возвращает их в нормализованном виде
-func (m *NameMatcher) normalizeNameParts(name string) []string {
+// NormalizeNameParts разбивает имя на части и возвращает их в нормализованном виде
+func (m *NameMatcher) NormalizeNameParts(name string) []string {
 	// Проверяем, содержит ли имя инициалы
 	hasInitials := strings.Contains(name, ".")
 
@@ -107,15 +113,18 @@ func (m *NameMatcher) normalizeNameParts(name string) []string {
 	return result
 }
 
-// Генерирует различные вариации имени, включая перестановки - закомментирован, так как не используется
-/*
-func (m *NameMatcher) getNameVariations(name string) []string {
+// GetNameVariations генерирует различные вариации имени, включая перестановки.
+// Результаты сохраняются в кэше вариаций экземпляра, если он инициализирован
+func (m *NameMatcher) GetNameVariations(name string) []string {
 	// Проверяем кэш
-	if variations, ok := m.nameVariantions[name]; ok {
+	m.mutex.RLock()
+	variations, ok := m.nameVariantions[name]
+	m.mutex.RUnlock()
+	if ok {
 		return variations
 	}
 
-	parts := m.normalizeNameParts(name)
+	parts := m.NormalizeNameParts(name)
 	if len(parts) == 0 {
 		return []string{}
 	}
@@ -127,7 +136,7 @@ func (m *NameMatcher) getNameVariations(name string) []string {
 	variationsMap[original] = true
 
 	// Проверяем на наличие инициалов
-	hasInitials := m.hasInitials(name)
+	hasInitials := m.HasInitials(name)
 
 	// Если есть хотя бы 2 части, добавляем перестановки
 	if len(parts) >= 2 {
@@ -190,7 +199,7 @@ func (m *NameMatcher) getNameVariations(name string) []string {
 	}
 
 	// Преобразуем map в слайс
-	variations := make([]string, 0, len(variationsMap))
+	variations = make([]string, 0, len(variationsMap))
 	for v := range variationsMap {
 		variations = append(variations, v)
 	}
@@ -199,10 +208,16 @@ func (m *NameMatcher) getNameVariations(name string) []string {
 	sort.Strings(variations)
 
 	// Сохраняем в кэш
-	m.nameVariantions[name] = variations
+	if m.nameVariantions != nil {
+		m.mutex.Lock()
+		if len(m.nameVariantions) >= m.Config.MaxCacheSize {
+			m.nameVariantions = make(map[string][]string)
+		}
+		m.nameVariantions[name] = variations
+		m.mutex.Unlock()
+	}
 
 	return variations
 }
-*/
 
 // Функции для работы с кэшем перенесены в cache.go
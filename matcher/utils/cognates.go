@@ -0,0 +1,274 @@
+package utils
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/x0rium/compareNames/matcher/translit"
+)
+
+// defaultCognates встроенные группы межъязыковых соответствий имен
+//
+//go:embed cognates.json
+var defaultCognates []byte
+
+// Оценки межъязыкового соответствия в CognateScore
+const (
+	cognateScoreDirect   = 1.0 // Обе формы входят в группу ("Пётр" - "Pierre")
+	cognateScoreNickname = 0.8 // Форма связана с группой через уменьшительное имя ("Петя" - "Pierre")
+)
+
+// CognateCluster группа соответствий одного имени на разных языках: "Пётр", "Peter",
+// "Pierre", "Pedro", "Piotr". В отличие от записей словаря имен формы группы равноправны.
+// Транслитерации кириллических форм и написания без диакритики ("Jose") учитываются автоматически
+type CognateCluster struct {
+	Gender string   `json:"gender,omitempty"`
+	Forms  []string `json:"forms"`
+}
+
+// cognateRef форма группы соответствий, давшая ключ индекса
+type cognateRef struct {
+	cluster int
+	form    int
+}
+
+// ParseCognates разбирает группы соответствий в формате JSON (массив CognateCluster)
+func ParseCognates(r io.Reader) ([]CognateCluster, error) {
+	var clusters []CognateCluster
+	if err := json.NewDecoder(r).Decode(&clusters); err != nil {
+		return nil, err
+	}
+	return clusters, nil
+}
+
+// AddCognates добавляет группы соответствий во время работы. Группа, у которой есть общая форма
+// с известной группой, объединяется с ней; заданный пол заменяет прежний.
+// Добавленные группы сохраняются при перезагрузке словаря из файлов
+func (d *NameDictionary) AddCognates(clusters ...CognateCluster) error {
+	normalized := make([]CognateCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		cluster, err := normalizeCluster(cluster)
+		if err != nil {
+			return err
+		}
+		normalized = append(normalized, cluster)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.runtimeCognates = append(d.runtimeCognates, normalized...)
+	d.cognates = mergeClusters(d.cognates, normalized)
+	d.changed()
+	return nil
+}
+
+// Cognates возвращает копию групп соответствий, в которые входит имя или его транслитерация.
+// Для пустого имени возвращаются все группы
+func (d *NameDictionary) Cognates(name string) []CognateCluster {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	var clusters []CognateCluster
+	if name == "" {
+		for _, cluster := range d.cognates {
+			clusters = append(clusters, CognateCluster{Gender: cluster.Gender, Forms: append([]string(nil), cluster.Forms...)})
+		}
+		return clusters
+	}
+
+	seen := make(map[int]bool)
+	for _, ref := range d.index.cognates[cognateKey(name)] {
+		if !seen[ref.cluster] {
+			seen[ref.cluster] = true
+			cluster := d.cognates[ref.cluster]
+			clusters = append(clusters, CognateCluster{Gender: cluster.Gender, Forms: append([]string(nil), cluster.Forms...)})
+		}
+	}
+	return clusters
+}
+
+// CognateScore оценивает межъязыковое соответствие двух слов: 1, если оба входят в одну
+// группу соответствий ("Пётр" - "Pierre", "Иосиф" - "José"), 0.8, если слово связано с группой
+// через форму из словаря имен ("Петя" - "Pierre"), и 0 для остальных слов. Транслитерации
+// одной формы ("Пётр" - "Pyotr") и формы одной записи словаря ("Иван" - "Ваня")
+// соответствиями не считаются: их учитывают транслитерация и словарь имен
+func (d *NameDictionary) CognateScore(word1, word2 string) float64 {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	key1, key2 := cognateKey(word1), cognateKey(word2)
+	if key1 == key2 {
+		return 0
+	}
+
+	direct1, direct2 := d.index.cognates[key1], d.index.cognates[key2]
+	all1, all2 := direct1, direct2
+	if len(all1) == 0 {
+		all1 = d.nicknameCognates(word1)
+	}
+	if len(all2) == 0 {
+		all2 = d.nicknameCognates(word2)
+	}
+	if sameCognateForm(all1, all2) {
+		return 0
+	}
+
+	switch {
+	case sameCognateCluster(direct1, direct2):
+		return cognateScoreDirect
+	case sameCognateCluster(all1, all2):
+		return cognateScoreNickname
+	}
+	return 0
+}
+
+// nicknameCognates возвращает формы групп соответствий для полных имен записей словаря,
+// в которых слово указано уменьшительной формой. Вызывается под блокировкой и только для слов
+// вне групп: форма группы в записи словаря ("Pierre" в записи "петр") остается самостоятельной
+func (d *NameDictionary) nicknameCognates(word string) []cognateRef {
+	word = strings.ToLower(word)
+	var refs []cognateRef
+	for _, i := range d.index.entries[word] {
+		if name := d.entries[i].Name; name != word {
+			refs = append(refs, d.index.cognates[cognateKey(name)]...)
+		}
+	}
+	return refs
+}
+
+// builtinCognates разбирает встроенные группы соответствий
+func builtinCognates() ([]CognateCluster, error) {
+	clusters, err := ParseCognates(bytes.NewReader(defaultCognates))
+	if err != nil {
+		return nil, err
+	}
+	for i, cluster := range clusters {
+		if clusters[i], err = normalizeCluster(cluster); err != nil {
+			return nil, err
+		}
+	}
+	return clusters, nil
+}
+
+// normalizeCluster приводит формы группы к нижнему регистру, убирает повторы и проверяет пол
+func normalizeCluster(cluster CognateCluster) (CognateCluster, error) {
+	cluster.Gender = strings.ToLower(strings.TrimSpace(cluster.Gender))
+	if cluster.Gender != GenderMale && cluster.Gender != GenderFemale && cluster.Gender != GenderUnknown {
+		return cluster, fmt.Errorf("cognate cluster: unknown gender %q", cluster.Gender)
+	}
+
+	forms := make([]string, 0, len(cluster.Forms))
+	for _, form := range cluster.Forms {
+		if form = strings.ToLower(strings.TrimSpace(form)); form != "" {
+			forms = appendUnique(forms, form)
+		}
+	}
+	if len(forms) < 2 {
+		return cluster, errors.New("cognate cluster: at least two forms are required")
+	}
+	cluster.Forms = forms
+	return cluster, nil
+}
+
+// mergeClusters добавляет группы соответствий: формы группы с общей формой (см. cognateKey)
+// объединяются с известной группой. Исходный срез не изменяется
+func mergeClusters(clusters, added []CognateCluster) []CognateCluster {
+	merged := make([]CognateCluster, len(clusters), len(clusters)+len(added))
+	copy(merged, clusters)
+
+	for _, cluster := range added {
+		target := -1
+		for i := range merged {
+			if sharesCognateForm(merged[i], cluster) {
+				target = i
+				break
+			}
+		}
+		if target < 0 {
+			merged = append(merged, cluster)
+			continue
+		}
+
+		existing := CognateCluster{Gender: merged[target].Gender, Forms: append([]string(nil), merged[target].Forms...)}
+		existing.Forms = appendUnique(existing.Forms, cluster.Forms...)
+		if cluster.Gender != GenderUnknown {
+			existing.Gender = cluster.Gender
+		}
+		merged[target] = existing
+	}
+	return merged
+}
+
+// sharesCognateForm проверяет, есть ли у групп соответствий общая форма
+func sharesCognateForm(cluster1, cluster2 CognateCluster) bool {
+	for _, form1 := range cluster1.Forms {
+		for _, form2 := range cluster2.Forms {
+			if cognateKey(form1) == cognateKey(form2) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// indexCognates добавляет в индекс ключи форм групп соответствий и их транслитераций
+// и пол форм. Формы с одинаковым ключом ("Петр", "Пётр") считаются одной формой
+func indexCognates(index *dictionaryIndex, clusters []CognateCluster) {
+	for i, cluster := range clusters {
+		forms := make(map[string]int, len(cluster.Forms))
+		for j, form := range cluster.Forms {
+			if _, ok := forms[cognateKey(form)]; !ok {
+				forms[cognateKey(form)] = j
+			}
+			ref := cognateRef{cluster: i, form: forms[cognateKey(form)]}
+
+			for _, variant := range translit.GetAllTransliterations(form) {
+				for _, word := range []string{variant, cognateKey(variant)} {
+					if gender, ok := index.cognateGenders[word]; ok && gender != cluster.Gender {
+						index.cognateGenders[word] = GenderUnknown
+					} else {
+						index.cognateGenders[word] = cluster.Gender
+					}
+				}
+				if refs := index.cognates[cognateKey(variant)]; len(refs) == 0 || refs[len(refs)-1] != ref {
+					index.cognates[cognateKey(variant)] = append(refs, ref)
+				}
+			}
+		}
+	}
+}
+
+// cognateKey ключ формы имени в индексе соответствий: нижний регистр, "ё" как "е", без диакритики
+func cognateKey(word string) string {
+	return translit.FoldDiacritics(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(word)), "ё", "е"))
+}
+
+// sameCognateCluster проверяет, есть ли среди форм одна группа соответствий
+func sameCognateCluster(refs1, refs2 []cognateRef) bool {
+	for _, ref1 := range refs1 {
+		for _, ref2 := range refs2 {
+			if ref1.cluster == ref2.cluster {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameCognateForm проверяет, есть ли среди форм одна и та же форма группы
+func sameCognateForm(refs1, refs2 []cognateRef) bool {
+	for _, ref1 := range refs1 {
+		for _, ref2 := range refs2 {
+			if ref1 == ref2 {
+				return true
+			}
+		}
+	}
+	return false
+}
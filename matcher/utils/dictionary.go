@@ -0,0 +1,470 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/x0rium/compareNames/matcher/translit"
+)
+
+// defaultNicknames встроенный словарь имен и уменьшительных форм
+//
+//go:embed nicknames.json
+var defaultNicknames []byte
+
+// Форматы файлов словаря
+const (
+	DictionaryFormatJSON = "json"
+	DictionaryFormatCSV  = "csv"
+)
+
+// DictionaryEntry запись словаря: полное имя, пол и связанные формы - уменьшительные
+// ("Ваня") и транслитерации ("Ivan"). Одна форма может относиться к нескольким именам
+// ("Саша" - Александр и Александра). Соответствия на других языках ("John", "Johann")
+// задаются группами CognateCluster
+type DictionaryEntry struct {
+	Name     string   `json:"name"`
+	Gender   string   `json:"gender,omitempty"`
+	Variants []string `json:"variants"`
+}
+
+// NameDictionary словарь имен, их форм и межъязыковых соответствий. Безопасен для конкурентного
+// использования: записи можно добавлять во время работы (Add, AddCognates) и перезагружать
+// из файлов (LoadFiles, Watch)
+type NameDictionary struct {
+	mutex           sync.RWMutex
+	entries         []DictionaryEntry
+	runtime         []DictionaryEntry // Записи, добавленные во время работы; сохраняются при перезагрузке
+	cognates        []CognateCluster
+	runtimeCognates []CognateCluster // Группы соответствий, добавленные во время работы
+	index           *dictionaryIndex // Перестраивается при каждом изменении
+	version         uint64
+}
+
+// dictionaryIndex индекс словаря для поиска по любой форме имени
+type dictionaryIndex struct {
+	entries  map[string][]int        // Форма имени -> записи, в которых она встречается
+	names    map[string][]int        // Полное имя -> записи с этим именем
+	genders  map[string]string       // Форма имени и ее транслитерации -> пол
+	cognates map[string][]cognateRef // Ключ формы (см. cognateKey) -> формы групп соответствий
+	// Пол форм групп соответствий. Формы групп не считаются именами при разборе ролей частей
+	cognateGenders map[string]string
+}
+
+// Общий словарь имен, загруженный из встроенных nicknames.json и cognates.json
+var (
+	dictionaryOnce    sync.Once
+	defaultDictionary *NameDictionary
+)
+
+// Dictionary возвращает общий словарь имен, используемый при разборе и сравнении имен
+func Dictionary() *NameDictionary {
+	dictionaryOnce.Do(func() {
+		defaultDictionary = NewNameDictionary()
+	})
+	return defaultDictionary
+}
+
+// NewNameDictionary создает словарь со встроенными записями из nicknames.json
+// и группами соответствий из cognates.json
+func NewNameDictionary() *NameDictionary {
+	d := &NameDictionary{}
+	if err := d.reset(nil); err != nil {
+		panic("utils: встроенный словарь имен поврежден: " + err.Error())
+	}
+	return d
+}
+
+// ParseDictionary разбирает записи словаря в формате JSON (массив DictionaryEntry) или CSV.
+// Строка CSV задает одно соответствие "имя,форма[,пол]"; строка заголовка "name,..." пропускается
+func ParseDictionary(r io.Reader, format string) ([]DictionaryEntry, error) {
+	switch format {
+	case DictionaryFormatJSON:
+		var entries []DictionaryEntry
+		if err := json.NewDecoder(r).Decode(&entries); err != nil {
+			return nil, err
+		}
+		for i, entry := range entries {
+			if strings.TrimSpace(entry.Name) == "" {
+				return nil, fmt.Errorf("dictionary entry %d: name is required", i)
+			}
+		}
+		return entries, nil
+
+	case DictionaryFormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+
+		var entries []DictionaryEntry
+		for i, record := range records {
+			if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "name") {
+				continue
+			}
+			if len(record) < 2 || strings.TrimSpace(record[0]) == "" {
+				return nil, fmt.Errorf("dictionary line %d: expected name,variant[,gender]", i+1)
+			}
+			entry := DictionaryEntry{Name: record[0], Variants: []string{record[1]}}
+			if len(record) > 2 {
+				entry.Gender = record[2]
+			}
+			entries = append(entries, entry)
+		}
+		return entries, nil
+	}
+
+	return nil, fmt.Errorf("unknown dictionary format %q", format)
+}
+
+// ParseDictionaryFile разбирает файл словаря; формат определяется по расширению (.json, .csv)
+func ParseDictionaryFile(path string) ([]DictionaryEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries, err := ParseDictionary(file, strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), "."))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// Add добавляет записи в словарь во время работы. Формы записи с уже известным
+// именем объединяются с имеющимися, заданный пол заменяет прежний.
+// Добавленные записи сохраняются при перезагрузке словаря из файлов
+func (d *NameDictionary) Add(entries ...DictionaryEntry) error {
+	normalized := make([]DictionaryEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry, err := normalizeEntry(entry)
+		if err != nil {
+			return err
+		}
+		normalized = append(normalized, entry)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.runtime = append(d.runtime, normalized...)
+	d.entries = mergeEntries(d.entries, normalized)
+	d.changed()
+	return nil
+}
+
+// LoadFiles заменяет содержимое словаря встроенными записями и записями из файлов JSON и CSV.
+// Записи, добавленные через Add, сохраняются. При ошибке словарь не изменяется
+func (d *NameDictionary) LoadFiles(paths ...string) error {
+	var loaded []DictionaryEntry
+	for _, path := range paths {
+		entries, err := ParseDictionaryFile(path)
+		if err != nil {
+			return err
+		}
+		loaded = append(loaded, entries...)
+	}
+	return d.reset(loaded)
+}
+
+// Watch перезагружает словарь из файлов при изменении их времени модификации,
+// проверяя файлы с интервалом interval, пока не отменен ctx. После каждой
+// перезагрузки вызывается onReload с ее результатом (nil при успехе)
+func (d *NameDictionary) Watch(ctx context.Context, interval time.Duration, onReload func(error), paths ...string) {
+	modTimes := func() []time.Time {
+		times := make([]time.Time, len(paths))
+		for i, path := range paths {
+			if info, err := os.Stat(path); err == nil {
+				times[i] = info.ModTime()
+			}
+		}
+		return times
+	}
+
+	last := modTimes()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := modTimes()
+			if equalTimes(current, last) {
+				continue
+			}
+			last = current
+			err := d.LoadFiles(paths...)
+			if onReload != nil {
+				onReload(err)
+			}
+		}
+	}
+}
+
+// Entries возвращает копию записей словаря, упорядоченных по имени
+func (d *NameDictionary) Entries() []DictionaryEntry {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	entries := make([]DictionaryEntry, len(d.entries))
+	for i, entry := range d.entries {
+		entries[i] = DictionaryEntry{Name: entry.Name, Gender: entry.Gender, Variants: append([]string(nil), entry.Variants...)}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+// Lookup возвращает записи, в которых встречается имя или его форма
+func (d *NameDictionary) Lookup(name string) []DictionaryEntry {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	var entries []DictionaryEntry
+	for _, i := range d.index.entries[strings.ToLower(name)] {
+		entry := d.entries[i]
+		entries = append(entries, DictionaryEntry{Name: entry.Name, Gender: entry.Gender, Variants: append([]string(nil), entry.Variants...)})
+	}
+	return entries
+}
+
+// Version возвращает номер версии словаря; он увеличивается при каждом изменении.
+// Используется для сброса кэшей, зависящих от словаря
+func (d *NameDictionary) Version() uint64 {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	return d.version
+}
+
+// Nicknames возвращает формы полного имени из всех записей с этим именем
+func (d *NameDictionary) Nicknames(name string) []string {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	var variants []string
+	for _, i := range d.index.names[strings.ToLower(name)] {
+		variants = appendUnique(variants, d.entries[i].Variants...)
+	}
+	return variants
+}
+
+// FullNames возвращает полные имена записей, в которых слово указано формой имени
+func (d *NameDictionary) FullNames(word string) []string {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	word = strings.ToLower(word)
+	var names []string
+	for _, i := range d.index.entries[word] {
+		if d.entries[i].Name != word {
+			names = appendUnique(names, d.entries[i].Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Related проверяет, относятся ли два слова к одной записи словаря: полное имя и его форма
+// или две формы одного имени ("Иван" - "Ваня", "Ваня" - "John")
+func (d *NameDictionary) Related(word1, word2 string) bool {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	index := d.index
+	entries2 := index.entries[strings.ToLower(word2)]
+	for _, i := range index.entries[strings.ToLower(word1)] {
+		for _, j := range entries2 {
+			if i == j {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsGivenName проверяет, есть ли слово среди имен и форм словаря с их транслитерациями
+func (d *NameDictionary) IsGivenName(word string) bool {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	_, ok := d.index.genders[strings.ToLower(word)]
+	return ok
+}
+
+// Gender возвращает пол имени по словарю, а для слов вне записей словаря - по группам соответствий.
+// Формы, общие для мужских и женских имен ("Саша", "Женя"), и неизвестные слова имеют неизвестный пол
+func (d *NameDictionary) Gender(word string) string {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	word = strings.ToLower(word)
+	if gender, ok := d.index.genders[word]; ok {
+		return gender
+	}
+	return d.index.cognateGenders[word]
+}
+
+// reset заменяет записи словаря встроенными, загруженными и добавленными во время работы
+func (d *NameDictionary) reset(loaded []DictionaryEntry) error {
+	builtin, err := ParseDictionary(bytes.NewReader(defaultNicknames), DictionaryFormatJSON)
+	if err != nil {
+		return err
+	}
+	cognates, err := builtinCognates()
+	if err != nil {
+		return err
+	}
+
+	var entries []DictionaryEntry
+	for _, group := range [][]DictionaryEntry{builtin, loaded} {
+		normalized := make([]DictionaryEntry, 0, len(group))
+		for _, entry := range group {
+			entry, err := normalizeEntry(entry)
+			if err != nil {
+				return err
+			}
+			normalized = append(normalized, entry)
+		}
+		entries = mergeEntries(entries, normalized)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.entries = mergeEntries(entries, d.runtime)
+	d.cognates = mergeClusters(cognates, d.runtimeCognates)
+	d.changed()
+	return nil
+}
+
+// changed перестраивает индекс и увеличивает версию; вызывается под блокировкой на запись
+func (d *NameDictionary) changed() {
+	d.index = buildDictionaryIndex(d.entries, d.cognates)
+	d.version++
+}
+
+// buildDictionaryIndex строит индекс записей по формам имен, индекс групп соответствий
+// и пол форм с транслитерациями
+func buildDictionaryIndex(entries []DictionaryEntry, cognates []CognateCluster) *dictionaryIndex {
+	index := &dictionaryIndex{
+		entries:        make(map[string][]int),
+		names:          make(map[string][]int),
+		genders:        make(map[string]string),
+		cognates:       make(map[string][]cognateRef),
+		cognateGenders: make(map[string]string),
+	}
+
+	for i, entry := range entries {
+		index.names[entry.Name] = append(index.names[entry.Name], i)
+		for _, word := range append([]string{entry.Name}, entry.Variants...) {
+			if known := index.entries[word]; len(known) == 0 || known[len(known)-1] != i {
+				index.entries[word] = append(known, i)
+			}
+			for _, variant := range translit.GetAllTransliterations(word) {
+				if gender, ok := index.genders[variant]; ok && gender != entry.Gender {
+					index.genders[variant] = GenderUnknown
+				} else {
+					index.genders[variant] = entry.Gender
+				}
+			}
+		}
+	}
+	indexCognates(index, cognates)
+
+	return index
+}
+
+// normalizeEntry приводит имя и формы записи к нижнему регистру и проверяет пол
+func normalizeEntry(entry DictionaryEntry) (DictionaryEntry, error) {
+	entry.Name = strings.ToLower(strings.TrimSpace(entry.Name))
+	if entry.Name == "" {
+		return entry, errors.New("dictionary entry: name is required")
+	}
+
+	entry.Gender = strings.ToLower(strings.TrimSpace(entry.Gender))
+	if entry.Gender != GenderMale && entry.Gender != GenderFemale && entry.Gender != GenderUnknown {
+		return entry, fmt.Errorf("dictionary entry %q: unknown gender %q", entry.Name, entry.Gender)
+	}
+
+	variants := make([]string, 0, len(entry.Variants))
+	for _, variant := range entry.Variants {
+		if variant = strings.ToLower(strings.TrimSpace(variant)); variant != "" && variant != entry.Name {
+			variants = appendUnique(variants, variant)
+		}
+	}
+	entry.Variants = variants
+	return entry, nil
+}
+
+// mergeEntries добавляет записи к словарю: формы записей с тем же именем объединяются,
+// заданный пол заменяет прежний. Исходный срез не изменяется
+func mergeEntries(entries, added []DictionaryEntry) []DictionaryEntry {
+	merged := make([]DictionaryEntry, len(entries), len(entries)+len(added))
+	copy(merged, entries)
+
+	positions := make(map[string]int, len(merged))
+	for i, entry := range merged {
+		positions[entry.Name] = i
+	}
+
+	for _, entry := range added {
+		i, ok := positions[entry.Name]
+		if !ok {
+			positions[entry.Name] = len(merged)
+			merged = append(merged, entry)
+			continue
+		}
+		existing := merged[i]
+		existing.Variants = appendUnique(append([]string(nil), existing.Variants...), entry.Variants...)
+		if entry.Gender != GenderUnknown {
+			existing.Gender = entry.Gender
+		}
+		merged[i] = existing
+	}
+
+	return merged
+}
+
+// appendUnique добавляет к срезу значения, которых в нем еще нет
+func appendUnique(values []string, added ...string) []string {
+	for _, value := range added {
+		found := false
+		for _, existing := range values {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// equalTimes сравнивает времена модификации файлов
+func equalTimes(a, b []time.Time) bool {
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"strings"
+)
+
+// Веса признаков рода в InferGender: отчество надежнее имени, имя надежнее фамилии
+const (
+	patronymicGenderWeight = 3
+	givenNameGenderWeight  = 2
+	surnameGenderWeight    = 1
+)
+
+// GivenNameGender определяет род имени по словарю мужских и женских имен (см. Dictionary).
+// Уменьшительные формы общих имен ("Саша", "Женя") и имена вне словаря имеют неизвестный род
+func GivenNameGender(name string) string {
+	return Dictionary().Gender(name)
+}
+
+// InferGender определяет род человека по разобранному имени. Признаки взвешиваются:
+// отчество (-ович, -овна, "оглы", "кызы") сильнее имени из словаря, имя сильнее
+// окончания фамилии (-ов/-ова, -ский/-ская). Косвенные падежи приводятся к именительному.
+// Если признаков нет или они уравновешивают друг друга, род неизвестен
+func InferGender(parsed ParsedName) string {
+	score := 0
+	vote := func(gender string, weight int) {
+		switch gender {
+		case GenderMale:
+			score += weight
+		case GenderFemale:
+			score -= weight
+		}
+	}
+
+	for _, part := range parsed.Parts {
+		switch part.Role {
+		case RolePatronymic:
+			vote(LemmatizePatronymic(part.Text).Gender, patronymicGenderWeight)
+		case RoleGiven:
+			// Часть вне словаря имен (роль определена по позиции) оценивается как фамилия
+			if lemma, ok := givenNameLemma(strings.ToLower(part.Text)); ok {
+				vote(lemma.Gender, givenNameGenderWeight)
+			} else {
+				vote(LemmatizeSurname(part.Text).Gender, surnameGenderWeight)
+			}
+		case RoleSurname:
+			vote(LemmatizeSurname(part.Text).Gender, surnameGenderWeight)
+		}
+	}
+
+	switch {
+	case score > 0:
+		return GenderMale
+	case score < 0:
+		return GenderFemale
+	}
+	return GenderUnknown
+}
+
+// GenderConflict проверяет, что роды двух имен известны и различаются
+func GenderConflict(parsed1, parsed2 ParsedName) bool {
+	gender1, gender2 := InferGender(parsed1), InferGender(parsed2)
+	return gender1 != GenderUnknown && gender2 != GenderUnknown && gender1 != gender2
+}
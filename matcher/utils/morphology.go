@@ -0,0 +1,353 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Род, определенный по форме части имени
+const (
+	GenderMale    = "male"
+	GenderFemale  = "female"
+	GenderUnknown = ""
+)
+
+// Lemma нормальная форма части имени
+type Lemma struct {
+	Text    string `json:"text"`              // Именительный падеж с учетом рода ("иванову" - "иванов")
+	Family  string `json:"family"`            // Мужская форма фамилии, общая для семьи ("иванова" - "иванов")
+	Gender  string `json:"gender,omitempty"`  // GenderMale, GenderFemale или GenderUnknown
+	Oblique bool   `json:"oblique,omitempty"` // Слово стояло в косвенном падеже
+}
+
+// surnameEnding окончание фамилии: окончание леммы, род и признак косвенного падежа
+type surnameEnding struct {
+	ending  string
+	lemma   string
+	gender  string
+	oblique bool
+}
+
+// Парадигмы склонения русских фамилий. Для неоднозначных окончаний указано несколько
+// вариантов, первый используется, если род не подсказан именем или отчеством
+var (
+	// Притяжательные фамилии на -ов, -ев, -ин: "Иванов", "Иванова", "Иванову", "Ивановым"
+	possessiveStems   = []string{"ов", "ев", "ёв", "ин", "ын"}
+	possessiveEndings = [][]surnameEnding{
+		{{"", "", GenderMale, false}},
+		{{"а", "а", GenderFemale, false}, {"а", "", GenderMale, true}},
+		{{"у", "", GenderMale, true}, {"у", "а", GenderFemale, true}},
+		{{"ым", "", GenderMale, true}},
+		{{"е", "", GenderMale, true}},
+		{{"ой", "а", GenderFemale, true}},
+		{{"ых", "", GenderUnknown, true}},
+		{{"ыми", "", GenderUnknown, true}},
+	}
+
+	// Прилагательные фамилии на -ский, -цкий: "Достоевский", "Достоевская", "Достоевского"
+	adjectivalStems   = []string{"ск", "цк"}
+	adjectivalEndings = [][]surnameEnding{
+		{{"ий", "ий", GenderMale, false}},
+		{{"ая", "ая", GenderFemale, false}},
+		{{"ого", "ий", GenderMale, true}},
+		{{"ому", "ий", GenderMale, true}},
+		{{"им", "ий", GenderMale, true}},
+		{{"ом", "ий", GenderMale, true}},
+		{{"ой", "ая", GenderFemale, true}, {"ой", "ой", GenderMale, false}},
+		{{"ую", "ая", GenderFemale, true}},
+		{{"их", "ий", GenderUnknown, true}},
+		{{"ими", "ий", GenderUnknown, true}},
+	}
+
+	// Латинские формы фамилий: окончание, мужская форма и род
+	latinSurnameEndings = []surnameEnding{
+		{"skaya", "sky", GenderFemale, false}, {"skaja", "sky", GenderFemale, false},
+		{"skiy", "sky", GenderMale, false}, {"skij", "sky", GenderMale, false},
+		{"skii", "sky", GenderMale, false}, {"sky", "sky", GenderMale, false}, {"ski", "sky", GenderMale, false},
+		{"ska", "sky", GenderFemale, false},
+		{"ova", "ov", GenderFemale, false}, {"eva", "ev", GenderFemale, false},
+		{"ina", "in", GenderFemale, false}, {"yna", "yn", GenderFemale, false},
+		{"ov", "ov", GenderMale, false}, {"ev", "ev", GenderMale, false},
+		{"in", "in", GenderMale, false}, {"yn", "yn", GenderMale, false},
+	}
+)
+
+// givenNameEndings окончания косвенных падежей имен и возможные окончания именительного падежа.
+// Вариант принимается, только если он есть в словаре имен
+var givenNameEndings = []struct {
+	ending string
+	lemmas []string
+}{
+	{"ией", []string{"ия"}}, {"ии", []string{"ия"}}, {"ию", []string{"ия"}},
+	{"лом", []string{"ел"}}, {"ла", []string{"ел"}}, {"лу", []string{"ел"}}, {"ле", []string{"ел"}},
+	{"ом", []string{""}}, {"ем", []string{"ь", "й"}}, {"ой", []string{"а"}}, {"ей", []string{"я"}},
+	{"а", []string{""}}, {"я", []string{"ь", "й"}}, {"у", []string{"", "а"}}, {"ю", []string{"ь", "й", "я"}},
+	{"е", []string{"", "а", "я"}}, {"ы", []string{"а"}}, {"и", []string{"а", "я", "ь"}},
+}
+
+// Окончания косвенных падежей отчеств и окончания именительного падежа
+var patronymicEndings = []surnameEnding{
+	{"ичем", "ич", GenderMale, true}, {"ича", "ич", GenderMale, true},
+	{"ичу", "ич", GenderMale, true}, {"иче", "ич", GenderMale, true}, {"ич", "ич", GenderMale, false},
+	{"вной", "вна", GenderFemale, true}, {"вны", "вна", GenderFemale, true},
+	{"вне", "вна", GenderFemale, true}, {"вну", "вна", GenderFemale, true}, {"вна", "вна", GenderFemale, false},
+	{"чной", "чна", GenderFemale, true}, {"чны", "чна", GenderFemale, true},
+	{"чне", "чна", GenderFemale, true}, {"чну", "чна", GenderFemale, true}, {"чна", "чна", GenderFemale, false},
+	{"ovich", "ovich", GenderMale, false}, {"evich", "evich", GenderMale, false},
+	{"ovna", "ovna", GenderFemale, false}, {"evna", "evna", GenderFemale, false}, {"ichna", "ichna", GenderFemale, false},
+}
+
+// minSurnameStem минимальная длина основы фамилии перед суффиксом (-ов, -ск)
+const minSurnameStem = 2
+
+// LemmatizeSurname приводит фамилию к именительному падежу и определяет род по окончанию:
+// "Иванову" - "иванов", "Ивановой" - "иванова", "Достоевского" - "достоевский".
+// Для неоднозначных форм ("Иванова" - женская фамилия или родительный падеж мужской)
+// выбирается именительный падеж. Слово, не похожее на склоняемую фамилию, возвращается
+// без изменений с неизвестным родом
+func LemmatizeSurname(word string) Lemma {
+	return surnameLemmas(strings.ToLower(word))[0]
+}
+
+// surnameLemmas возвращает варианты леммы фамилии в порядке предпочтения
+func surnameLemmas(word string) []Lemma {
+	if lemmas := paradigmLemmas(word, possessiveStems, possessiveEndings, ""); lemmas != nil {
+		return lemmas
+	}
+	if lemmas := paradigmLemmas(word, adjectivalStems, adjectivalEndings, "ий"); lemmas != nil {
+		return lemmas
+	}
+
+	for _, e := range latinSurnameEndings {
+		if stem, ok := cutEnding(word, e.ending); ok {
+			return []Lemma{{Text: word, Family: stem + e.lemma, Gender: e.gender}}
+		}
+	}
+
+	return []Lemma{{Text: word, Family: word}}
+}
+
+// paradigmLemmas подбирает самое длинное окончание парадигмы и возвращает варианты леммы.
+// familyEnding - окончание мужской формы после основы с суффиксом
+func paradigmLemmas(word string, stems []string, paradigm [][]surnameEnding, familyEnding string) []Lemma {
+	var best []surnameEnding
+	var bestStem string
+	for _, suffix := range stems {
+		for _, endings := range paradigm {
+			stem, ok := cutEnding(word, suffix+endings[0].ending)
+			if !ok || (best != nil && len(endings[0].ending) <= len(best[0].ending)) {
+				continue
+			}
+			best, bestStem = endings, stem+suffix
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	lemmas := make([]Lemma, len(best))
+	for i, e := range best {
+		lemmas[i] = Lemma{Text: bestStem + e.lemma, Family: bestStem + familyEnding, Gender: e.gender, Oblique: e.oblique}
+	}
+	return lemmas
+}
+
+// LemmatizeGivenName приводит имя к именительному падежу по словарю имен (см. IsGivenName):
+// "Ивану" - "иван", "Анны" - "анна", "Сергея" - "сергей". Род определяется по словарю
+// (см. GivenNameGender). Если ни одна форма не найдена в словаре, слово возвращается без изменений
+func LemmatizeGivenName(word string) Lemma {
+	lemma, _ := givenNameLemma(strings.ToLower(word))
+	return lemma
+}
+
+// givenNameLemma возвращает лемму имени и признак того, что она найдена в словаре
+func givenNameLemma(word string) (Lemma, bool) {
+	if IsGivenName(word) {
+		return Lemma{Text: word, Family: word, Gender: GivenNameGender(word)}, true
+	}
+
+	for _, e := range givenNameEndings {
+		stem, ok := cutEnding(word, e.ending)
+		if !ok {
+			continue
+		}
+		for _, ending := range e.lemmas {
+			if candidate := stem + ending; IsGivenName(candidate) {
+				return Lemma{Text: candidate, Family: candidate, Gender: GivenNameGender(candidate), Oblique: true}, true
+			}
+		}
+	}
+
+	return Lemma{Text: word, Family: word}, false
+}
+
+// LemmatizePatronymic приводит отчество к именительному падежу и определяет род:
+// "Ивановича" - "иванович", "Петровной" - "петровна". Тюркские "оглы" и "кызы"
+// определяют мужской и женский род
+func LemmatizePatronymic(word string) Lemma {
+	lemma, _ := patronymicLemma(strings.ToLower(word))
+	return lemma
+}
+
+// patronymicLemma возвращает лемму отчества и признак того, что слово похоже на отчество
+func patronymicLemma(word string) (Lemma, bool) {
+	switch word {
+	case "оглы", "ogly", "улы", "uly":
+		return Lemma{Text: word, Family: word, Gender: GenderMale}, true
+	case "кызы", "kyzy":
+		return Lemma{Text: word, Family: word, Gender: GenderFemale}, true
+	}
+
+	for _, e := range patronymicEndings {
+		if stem, ok := cutEnding(word, e.ending); ok && utf8.RuneCountInString(stem) >= minSurnameStem {
+			lemma := stem + e.lemma
+			return Lemma{Text: lemma, Family: lemma, Gender: e.gender, Oblique: e.oblique}, true
+		}
+	}
+
+	return Lemma{Text: word, Family: word}, false
+}
+
+// NormalizeMorphology приводит фамилию, имя и отчество разобранного имени к именительному
+// падежу с сохранением регистра: "Иванову Ивану Петровичу" - "Иванов Иван Петрович".
+// Форма фамилии выбирается по роду, который подсказывают имя и отчество: в "Петрова Ивана"
+// фамилия - родительный падеж "Петров", в "Петрова Анна" - женская фамилия.
+// Если роли частей определены только по позиции, лемма подбирается по форме слова,
+// а имя разбирается заново
+func NormalizeMorphology(parsed ParsedName) ParsedName {
+	parts := make([]NamePart, len(parsed.Parts))
+	copy(parts, parsed.Parts)
+	done := make([]bool, len(parts))
+	changed := false
+
+	setLemma := func(i int, lemma Lemma) {
+		done[i] = true
+		if text := matchCase(lemma.Text, parts[i].Text); text != parts[i].Text {
+			parts[i].Text = text
+			changed = true
+		}
+	}
+
+	// Имена и отчества определяют род, по которому выбирается форма фамилии
+	gender := GenderUnknown
+	for i, part := range parts {
+		if !lemmatizedRole(part.Role) {
+			continue
+		}
+		word := strings.ToLower(part.Text)
+		var lemma Lemma
+		var ok bool
+		if part.Role == RoleGiven || !parsed.Confident {
+			lemma, ok = givenNameLemma(word)
+		}
+		if !ok && (part.Role == RolePatronymic || !parsed.Confident) {
+			lemma, ok = patronymicLemma(word)
+		}
+		if !ok {
+			continue
+		}
+		setLemma(i, lemma)
+		if gender == GenderUnknown {
+			gender = lemma.Gender
+		}
+	}
+
+	for i, part := range parts {
+		if done[i] || !lemmatizedRole(part.Role) || (parsed.Confident && part.Role != RoleSurname) {
+			continue
+		}
+		lemmas := surnameLemmas(strings.ToLower(part.Text))
+		lemma := lemmas[0]
+		for _, candidate := range lemmas {
+			if gender != GenderUnknown && candidate.Gender == gender {
+				lemma = candidate
+				break
+			}
+		}
+		setLemma(i, lemma)
+	}
+
+	if !changed {
+		return parsed
+	}
+	if !parsed.Confident {
+		return ParseName(partsText(parts))
+	}
+	return buildParsedName(parts, parsed.Confident)
+}
+
+// FamilyForm заменяет фамилию мужской формой, общей для членов семьи: "Иванова" - "Иванов",
+// "Достоевская" - "Достоевский"
+func FamilyForm(parsed ParsedName) ParsedName {
+	parts := make([]NamePart, len(parsed.Parts))
+	copy(parts, parsed.Parts)
+	for i, part := range parts {
+		if part.Role == RoleSurname {
+			parts[i].Text = matchCase(LemmatizeSurname(part.Text).Family, part.Text)
+		}
+	}
+	return buildParsedName(parts, parsed.Confident)
+}
+
+// GenderPairedSurnames проверяет, что фамилии двух имен - мужская и женская формы
+// одной фамилии ("Иванов" - "Иванова", "Dostoevsky" - "Dostoevskaya")
+func GenderPairedSurnames(parsed1, parsed2 ParsedName) bool {
+	surnames1, surnames2 := strings.Fields(parsed1.Surname), strings.Fields(parsed2.Surname)
+	if len(surnames1) == 0 || len(surnames1) != len(surnames2) {
+		return false
+	}
+
+	paired := false
+	for i := range surnames1 {
+		lemma1, lemma2 := LemmatizeSurname(surnames1[i]), LemmatizeSurname(surnames2[i])
+		if lemma1.Family != lemma2.Family {
+			return false
+		}
+		if lemma1.Gender != GenderUnknown && lemma2.Gender != GenderUnknown && lemma1.Gender != lemma2.Gender {
+			paired = true
+		}
+	}
+	return paired
+}
+
+// Text возвращает части имени через пробел в исходном порядке
+func (p ParsedName) Text() string {
+	return partsText(p.Parts)
+}
+
+// lemmatizedRole проверяет, изменяется ли часть с ролью по падежам
+func lemmatizedRole(role string) bool {
+	return role == RoleSurname || role == RoleGiven || role == RolePatronymic
+}
+
+// partsText соединяет тексты частей через пробел
+func partsText(parts []NamePart) string {
+	texts := make([]string, len(parts))
+	for i, part := range parts {
+		texts[i] = part.Text
+	}
+	return strings.Join(texts, " ")
+}
+
+// cutEnding отрезает окончание, если перед ним остается основа не короче minSurnameStem
+func cutEnding(word, ending string) (string, bool) {
+	if !strings.HasSuffix(word, ending) {
+		return "", false
+	}
+	stem := strings.TrimSuffix(word, ending)
+	return stem, utf8.RuneCountInString(stem) >= minSurnameStem
+}
+
+// matchCase переносит регистр исходного слова на лемму: "ИВАНОВУ" - "ИВАНОВ", "Ивану" - "Иван"
+func matchCase(lemma, original string) string {
+	if original == strings.ToUpper(original) && original != strings.ToLower(original) {
+		return strings.ToUpper(lemma)
+	}
+	first, _ := utf8.DecodeRuneInString(original)
+	if unicode.IsUpper(first) {
+		r, size := utf8.DecodeRuneInString(lemma)
+		return string(unicode.ToUpper(r)) + lemma[size:]
+	}
+	return lemma
+}
@@ -0,0 +1,316 @@
+package utils
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/x0rium/compareNames/matcher/translit"
+)
+
+// Роли частей имени
+const (
+	RoleSurname    = "surname"    // Фамилия
+	RoleGiven      = "given"      // Имя
+	RolePatronymic = "patronymic" // Отчество
+	RoleInitial    = "initial"    // Инициал
+	RoleParticle   = "particle"   // Частица фамилии ("фон", "van", "de")
+	RoleSuffix     = "suffix"     // Суффикс ("мл.", "Jr.", "III")
+)
+
+// Окончания для определения роли части имени
+var (
+	PatronymicSuffixes = []string{"ович", "евич", "ьич", "овна", "евна", "ична", "инична", "йович", "ївна",
+		"ovich", "evich", "ovna", "evna", "ichna"}
+	SurnameSuffixes = []string{"ов", "ев", "ёв", "ин", "ын", "ова", "ева", "ёва", "ина", "ына",
+		"ский", "цкий", "ская", "цкая", "енко", "ук", "юк", "ян", "швили", "дзе",
+		"ov", "ev", "in", "ova", "eva", "ina", "sky", "skiy", "skii", "skaya", "enko", "uk", "yan"}
+)
+
+// Отдельные слова с известной ролью
+var (
+	patronymicMarkers = map[string]bool{"оглы": true, "кызы": true, "улы": true, "ogly": true, "kyzy": true, "uly": true}
+	nameParticles     = map[string]bool{
+		"фон": true, "ван": true, "дер": true, "де": true, "ди": true, "да": true, "ла": true, "ле": true, "дель": true,
+		"von": true, "van": true, "der": true, "den": true, "de": true, "di": true, "da": true, "la": true, "le": true,
+		"del": true, "della": true, "du": true, "dos": true, "das": true, "ibn": true, "bin": true, "al": true, "el": true,
+	}
+	nameSuffixes = map[string]bool{
+		"мл": true, "ст": true, "младший": true, "старший": true,
+		"jr": true, "sr": true, "ii": true, "iii": true, "iv": true,
+	}
+)
+
+// NamePart часть имени с ролью
+type NamePart struct {
+	Text string `json:"text"`
+	Role string `json:"role"`
+}
+
+// ParsedName имя, разобранное на части по ролям
+type ParsedName struct {
+	Parts      []NamePart `json:"parts"`                // Части в исходном порядке
+	Surname    string     `json:"surname,omitempty"`    // Фамилия; части двойной фамилии через пробел
+	GivenName  string     `json:"given_name,omitempty"` // Имя; второе имя через пробел
+	Patronymic string     `json:"patronymic,omitempty"` // Отчество
+	Initials   []string   `json:"initials,omitempty"`
+	Particles  []string   `json:"particles,omitempty"`
+	Suffixes   []string   `json:"suffixes,omitempty"`
+	// Confident роли частей определены по окончаниям, словарю имен и исключению,
+	// а не только по позиции в имени
+	Confident bool `json:"confident"`
+}
+
+// ParseName разбирает имя на части с сохранением регистра и определяет роль каждой: инициал, частица, суффикс,
+// отчество по окончанию (-ович, -евна, -ична), имя по словарю имен (см. IsGivenName),
+// фамилия по окончанию (-ов, -ина, -ский). Отчество перед именем считается фамилией
+// ("Рабинович Михаил"). Оставшиеся части получают недостающую роль; если определить
+// ее можно только по позиции, для кириллицы используется порядок "Фамилия Имя Отчество",
+// для латиницы - "Имя Фамилия", а Confident сбрасывается
+func ParseName(name string) ParsedName {
+	texts := splitNameTokens(name)
+	tokens := make([]string, len(texts))
+	for i, text := range texts {
+		tokens[i] = strings.ToLower(text)
+	}
+	roles := make([]string, len(tokens))
+	parsed := ParsedName{Confident: true}
+
+	for i, token := range tokens {
+		roles[i] = tokenRole(token, i, len(tokens))
+	}
+
+	// Тюркское отчество пишется двумя словами: "Рашид оглы"
+	for i := 1; i < len(tokens); i++ {
+		if patronymicMarkers[tokens[i]] && roles[i-1] != RoleSurname {
+			roles[i-1] = RolePatronymic
+		}
+	}
+
+	// Из нескольких отчеств отчеством остается последнее, остальные - фамилии на -ович
+	// ("Абрамович Роман Аркадьевич"). Отчество перед именем без другой фамилии - тоже
+	// фамилия ("Рабинович Михаил"), а при наличии фамилии - переставленное отчество
+	given := indexOfRole(roles, RoleGiven)
+	lastPatronymic := lastIndexOfRole(roles, RolePatronymic)
+	hasSurname := indexOfRole(roles, RoleSurname) >= 0
+	for i, role := range roles {
+		if role != RolePatronymic || patronymicMarkers[tokens[i]] || (i+1 < len(tokens) && patronymicMarkers[tokens[i+1]]) {
+			continue
+		}
+		if i < lastPatronymic || (given >= 0 && i < given && !hasSurname) {
+			roles[i] = RoleSurname
+		}
+	}
+
+	// Несколько имен без фамилии: одно из них - фамилия, совпавшая с именем ("Роман Иван")
+	if indexOfRole(roles, RoleSurname) < 0 && countRole(roles, RoleGiven) > 1 {
+		if translit.IsCyrillic(name) {
+			roles[indexOfRole(roles, RoleGiven)] = RoleSurname
+		} else {
+			roles[lastIndexOfRole(roles, RoleGiven)] = RoleSurname
+		}
+		parsed.Confident = false
+	}
+
+	assignUnknownRoles(roles, translit.IsCyrillic(name), &parsed)
+
+	parts := make([]NamePart, len(texts))
+	for i, token := range texts {
+		parts[i] = NamePart{Text: token, Role: roles[i]}
+	}
+
+	return buildParsedName(parts, parsed.Confident)
+}
+
+// buildParsedName собирает разобранное имя из частей с ролями
+func buildParsedName(parts []NamePart, confident bool) ParsedName {
+	parsed := ParsedName{Parts: parts, Confident: confident}
+	for _, part := range parts {
+		switch part.Role {
+		case RoleSurname:
+			parsed.Surname = joinPart(parsed.Surname, part.Text)
+		case RoleGiven:
+			parsed.GivenName = joinPart(parsed.GivenName, part.Text)
+		case RolePatronymic:
+			parsed.Patronymic = joinPart(parsed.Patronymic, part.Text)
+		case RoleInitial:
+			parsed.Initials = append(parsed.Initials, part.Text)
+		case RoleParticle:
+			parsed.Particles = append(parsed.Particles, part.Text)
+		case RoleSuffix:
+			parsed.Suffixes = append(parsed.Suffixes, part.Text)
+		}
+	}
+	return parsed
+}
+
+// Ordered возвращает части имени в порядке "частицы фамилия имя отчество инициалы суффиксы"
+func (p ParsedName) Ordered() []string {
+	var parts []string
+	parts = append(parts, p.Particles...)
+	for _, part := range []string{p.Surname, p.GivenName, p.Patronymic} {
+		if part != "" {
+			parts = append(parts, strings.Fields(part)...)
+		}
+	}
+	parts = append(parts, p.Initials...)
+	parts = append(parts, p.Suffixes...)
+	return parts
+}
+
+// HasRole проверяет, есть ли в имени часть с указанной ролью
+func (p ParsedName) HasRole(role string) bool {
+	for _, part := range p.Parts {
+		if part.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// assignUnknownRoles назначает роли частям, не определенным по окончаниям и словарю.
+// Единственная оставшаяся часть получает недостающую роль фамилии или имени,
+// остальные распределяются по позиции
+func assignUnknownRoles(roles []string, cyrillic bool, parsed *ParsedName) {
+	unknown := 0
+	for _, role := range roles {
+		if role == "" {
+			unknown++
+		}
+	}
+	if unknown == 0 {
+		return
+	}
+
+	hasSurname := indexOfRole(roles, RoleSurname) >= 0
+	hasGiven := indexOfRole(roles, RoleGiven) >= 0
+	hasInitials := indexOfRole(roles, RoleInitial) >= 0
+	if unknown == 1 && !hasSurname && (hasGiven || hasInitials) {
+		// Имя или инициалы известны: оставшаяся часть - фамилия ("J Smith")
+		roles[indexOfRole(roles, "")] = RoleSurname
+		return
+	}
+	if unknown == 1 && hasSurname && !hasGiven {
+		roles[indexOfRole(roles, "")] = RoleGiven
+		return
+	}
+
+	parsed.Confident = false
+
+	// Порядок ролей по позиции: "Фамилия Имя Отчество" для кириллицы, "Имя Фамилия" для латиницы
+	order := []string{RoleSurname, RoleGiven, RolePatronymic}
+	if !cyrillic {
+		order = []string{RoleGiven, RoleSurname}
+	}
+	next := 0
+	for i, role := range roles {
+		if role != "" {
+			continue
+		}
+		for next < len(order) && indexOfRole(roles, order[next]) >= 0 {
+			next++
+		}
+		if next < len(order) {
+			roles[i] = order[next]
+		} else {
+			// Лишние части считаем вторым именем
+			roles[i] = RoleGiven
+		}
+	}
+
+	// В латинском порядке последняя часть - фамилия ("John Paul Smith")
+	if !cyrillic && indexOfRole(roles, RoleSurname) >= 0 {
+		last := len(roles) - 1
+		for last >= 0 && roles[last] != RoleGiven && roles[last] != RoleSurname {
+			last--
+		}
+		if last >= 0 && roles[last] == RoleGiven {
+			roles[indexOfRole(roles, RoleSurname)] = RoleGiven
+			roles[last] = RoleSurname
+		}
+	}
+}
+
+// tokenRole определяет роль части имени по ее виду, окончанию и словарю имен.
+// Пустая строка означает, что роль определяется позицией
+func tokenRole(token string, position, total int) string {
+	length := utf8.RuneCountInString(token)
+	switch {
+	case length == 1:
+		return RoleInitial
+	case nameParticles[token] && position < total-1:
+		return RoleParticle
+	case nameSuffixes[token] && position > 0:
+		return RoleSuffix
+	case patronymicMarkers[token] && position > 0:
+		return RolePatronymic
+	case length >= 5 && hasAnySuffix(token, PatronymicSuffixes):
+		return RolePatronymic
+	case IsGivenName(token):
+		return RoleGiven
+	case length >= 5 && hasAnySuffix(token, SurnameSuffixes):
+		return RoleSurname
+	}
+	return ""
+}
+
+// splitNameTokens разбивает имя на части по пробелам, дефисам, запятым и точкам инициалов
+func splitNameTokens(name string) []string {
+	return strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '-' || r == '.' || r == ','
+	})
+}
+
+// IsGivenName проверяет, есть ли слово в словаре имен (полные и уменьшительные
+// формы и их транслитерации, см. Dictionary)
+func IsGivenName(word string) bool {
+	return Dictionary().IsGivenName(word)
+}
+
+// hasAnySuffix проверяет, заканчивается ли слово одним из окончаний
+func hasAnySuffix(word string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(word, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// indexOfRole возвращает индекс первой части с ролью или -1
+func indexOfRole(roles []string, role string) int {
+	for i, r := range roles {
+		if r == role {
+			return i
+		}
+	}
+	return -1
+}
+
+// lastIndexOfRole возвращает индекс последней части с ролью или -1
+func lastIndexOfRole(roles []string, role string) int {
+	for i := len(roles) - 1; i >= 0; i-- {
+		if roles[i] == role {
+			return i
+		}
+	}
+	return -1
+}
+
+// countRole считает части с ролью
+func countRole(roles []string, role string) int {
+	count := 0
+	for _, r := range roles {
+		if r == role {
+			count++
+		}
+	}
+	return count
+}
+
+// joinPart дописывает часть к значению через пробел
+func joinPart(value, part string) string {
+	if value == "" {
+		return part
+	}
+	return value + " " + part
+}
@@ -1,121 +1,31 @@
 package utils
 
 import (
+	"bytes"
 	"strings"
 )
 
-// Nicknames карта уменьшительных имен
-var Nicknames = map[string][]string{
-	// Русские имена
-	"александр":  {"саша", "шура", "саня", "алекс"},
-	"алексей":    {"леша", "лёша", "алеша", "алёша", "лёха", "леха"},
-	"анатолий":   {"толя", "толик"},
-	"андрей":     {"андрюша", "дрюня"},
-	"антон":      {"антоша", "тоша", "тоха"},
-	"артем":      {"тема", "артемка", "тёма"},
-	"борис":      {"боря", "борька"},
-	"вадим":      {"вадик", "вадя"},
-	"валентин":   {"валя", "валик"},
-	"валерий":    {"валера", "валерка"},
-	"василий":    {"вася", "васька", "васек", "васёк"},
-	"виктор":     {"витя", "витька", "витек", "витёк"},
-	"виталий":    {"виталик", "виталя"},
-	"владимир":   {"вова", "володя", "вовка", "вовочка", "владик"},
-	"владислав":  {"влад", "владик", "слава"},
-	"вячеслав":   {"слава", "славик"},
-	"геннадий":   {"гена", "генка", "геша"},
-	"георгий":    {"гоша", "жора", "гера"},
-	"григорий":   {"гриша", "гришка", "гриня"},
-	"даниил":     {"даня", "данька", "данила"},
-	"денис":      {"дениска", "деня"},
-	"дмитрий":    {"дима", "димка", "митя"},
-	"евгений":    {"женя", "женька", "жека"},
-	"егор":       {"егорка", "гоша"},
-	"иван":       {"ваня", "ванька", "ванечка"},
-	"игорь":      {"игорек", "игорёк", "гарик"},
-	"илья":       {"ильюша", "илюша"},
-	"кирилл":     {"кирюша", "кир"},
-	"константин": {"костя", "костик", "кост"},
-	"леонид":     {"лёня", "леня", "лёнчик", "ленчик"},
-	"максим":     {"макс", "максик", "максимка"},
-	"михаил":     {"миша", "мишка", "миха", "мишаня"},
-	"никита":     {"никитка", "ник", "никитос"},
-	"николай":    {"коля", "колька", "николка", "ник"},
-	"олег":       {"олежка", "олежек", "олежик"},
-	"павел":      {"паша", "пашка", "павлик"},
-	"петр":       {"петя", "петька", "петруха"},
-	"роман":      {"рома", "ромка", "ромчик"},
-	"сергей":     {"серега", "серёга", "сережа", "серёжа"},
-	"станислав":  {"стас", "славик", "слава"},
-	"степан":     {"стёпа", "степа", "стёпка", "степка"},
-	"тимофей":    {"тима", "тимоха", "тимоша"},
-	"федор":      {"федя", "федька", "федюня"},
-	"юрий":       {"юра", "юрка", "юрчик"},
-	"ярослав":    {"яра", "ярик", "слава"},
-
-	// Женские имена
-	"александра": {"саша", "шура", "саня", "алекс"},
-	"алена":      {"аленка", "аленушка", "алёна", "алёнка", "алёнушка"},
-	"алина":      {"алинка", "аля"},
-	"анастасия":  {"настя", "настенька", "ася"},
-	"анна":       {"аня", "анечка", "анька", "анюта"},
-	"валентина":  {"валя", "валюша", "тина"},
-	"валерия":    {"лера", "лерочка", "валя"},
-	"вера":       {"верочка", "верка"},
-	"виктория":   {"вика", "викуля", "викуся"},
-	"галина":     {"галя", "галочка", "галка"},
-	"дарья":      {"даша", "дашенька", "дашка"},
-	"евгения":    {"женя", "женечка"},
-	"екатерина":  {"катя", "катенька", "катюша", "катерина"},
-	"елена":      {"лена", "леночка", "ленка", "еленка"},
-	"елизавета":  {"лиза", "лизочка", "лизка", "лизавета"},
-	"ирина":      {"ира", "ирочка", "иришка", "иринка"},
-	"кристина":   {"кристи", "крис", "кристинка"},
-	"лариса":     {"лара", "ларочка", "лариска"},
-	"любовь":     {"люба", "любочка", "любаша"},
-	"людмила":    {"люда", "людочка", "мила", "люся"},
-	"маргарита":  {"рита", "риточка", "маргоша"},
-	"марина":     {"мариша", "маришка", "мариночка"},
-	"мария":      {"маша", "машенька", "машка", "маня"},
-	"надежда":    {"надя", "наденька", "надюша"},
-	"наталья":    {"наташа", "наташенька", "наталия", "ната"},
-	"нина":       {"ниночка", "нинуля", "нинуша"},
-	"оксана":     {"ксюша", "оксаночка", "ксана"},
-	"ольга":      {"оля", "оленька", "олечка", "ольчик"},
-	"полина":     {"поля", "полинка", "полюшка"},
-	"светлана":   {"света", "светочка", "светик", "светланка"},
-	"софья":      {"соня", "сонечка", "софа"},
-	"татьяна":    {"таня", "танечка", "танюша"},
-	"юлия":       {"юля", "юленька", "юлька"},
-	"яна":        {"яночка", "янка"},
-
-	// Английские имена
-	"alexander":   {"alex", "al", "alec", "sandy", "sasha"},
-	"anthony":     {"tony", "ant", "toni"},
-	"benjamin":    {"ben", "benji", "benny"},
-	"charles":     {"charlie", "chuck", "chaz"},
-	"christopher": {"chris", "topher", "kit"},
-	"daniel":      {"dan", "danny", "dani"},
-	"david":       {"dave", "davey", "davy"},
-	"edward":      {"ed", "eddie", "ted", "teddy"},
-	"elizabeth":   {"liz", "lizzy", "beth", "betty", "eliza"},
-	"james":       {"jim", "jimmy", "jamie"},
-	"jennifer":    {"jen", "jenny", "jenn"},
-	"john":        {"johnny", "jack", "jock"},
-	"joseph":      {"joe", "joey", "jo"},
-	"katherine":   {"kate", "katie", "kathy", "kat"},
-	"margaret":    {"maggie", "meg", "peggy"},
-	"matthew":     {"matt", "matty"},
-	"michael":     {"mike", "mikey", "mick"},
-	"nicholas":    {"nick", "nicky", "nico"},
-	"patrick":     {"pat", "patty", "paddy"},
-	"peter":       {"pete", "petey"},
-	"richard":     {"rick", "ricky", "dick", "richie"},
-	"robert":      {"rob", "robbie", "bob", "bobby"},
-	"samuel":      {"sam", "sammy"},
-	"steven":      {"steve", "stevie"},
-	"thomas":      {"tom", "tommy"},
-	"william":     {"will", "bill", "billy", "willy"},
+// Nicknames карта уменьшительных имен: полное имя в нижнем регистре -> формы имени.
+// Заполняется встроенными записями словаря при загрузке пакета.
+//
+// Deprecated: карта не содержит записей, добавленных в словарь во время работы или загруженных
+// из файлов, а ее изменение не влияет на сравнение. Используйте Dictionary().Nicknames и Dictionary().Lookup
+var Nicknames = builtinNicknames()
+
+// builtinNicknames строит карту Nicknames по встроенным записям словаря
+func builtinNicknames() map[string][]string {
+	entries, err := ParseDictionary(bytes.NewReader(defaultNicknames), DictionaryFormatJSON)
+	if err != nil {
+		return map[string][]string{}
+	}
+	nicknames := make(map[string][]string, len(entries))
+	for _, entry := range entries {
+		name := strings.ToLower(entry.Name)
+		for _, variant := range entry.Variants {
+			nicknames[name] = appendUnique(nicknames[name], strings.ToLower(variant))
+		}
+	}
+	return nicknames
 }
 
 // GetNameVariations генерирует различные вариации имени, включая перестановки и уменьшительные формы
@@ -197,7 +107,7 @@ func GetNameVariations(name string) []string {
 	// Добавляем уменьшительные формы имен
 	for i, part := range parts {
 		// Проверяем, есть ли для этой части уменьшительные формы
-		if nicknames, ok := Nicknames[strings.ToLower(part)]; ok {
+		if nicknames := Dictionary().Nicknames(part); len(nicknames) > 0 {
 			for _, nickname := range nicknames {
 				// Создаем копию частей
 				newParts := make([]string, len(parts))
@@ -236,15 +146,9 @@ func GetNameVariations(name string) []string {
 // IsNickname проверяет, является ли имя уменьшительной формой другого имени
 // Возвращает полное имя и флаг, является ли имя уменьшительной формой
 func IsNickname(name string) (string, bool) {
-	nameLower := strings.ToLower(name)
-
-	// Проверяем все полные имена
-	for fullName, nicknames := range Nicknames {
-		for _, nickname := range nicknames {
-			if nickname == nameLower {
-				return fullName, true
-			}
-		}
+	// Если форма относится к нескольким именам, берется первое по алфавиту
+	if fullNames := Dictionary().FullNames(name); len(fullNames) > 0 {
+		return fullNames[0], true
 	}
 
 	return "", false
@@ -261,16 +165,14 @@ func GetFullName(name string) string {
 
 // GetAllNicknames возвращает все уменьшительные формы для имени
 func GetAllNicknames(name string) []string {
-	nameLower := strings.ToLower(name)
-
 	// Проверяем, является ли имя полным
-	if nicknames, ok := Nicknames[nameLower]; ok {
+	if nicknames := Dictionary().Nicknames(name); len(nicknames) > 0 {
 		return nicknames
 	}
 
 	// Проверяем, является ли имя уменьшительной формой
-	if fullName, isNickname := IsNickname(nameLower); isNickname {
-		return Nicknames[fullName]
+	if fullName, isNickname := IsNickname(name); isNickname {
+		return Dictionary().Nicknames(fullName)
 	}
 
 	return []string{}
@@ -4,8 +4,13 @@ import (
 	"regexp"
 	"strings"
 	"unicode"
+
+	"github.com/x0rium/compareNames/matcher/translit"
 )
 
+// spacesRegexp последовательность пробельных символов
+var spacesRegexp = regexp.MustCompile(`\s+`)
+
 // PreprocessName предобработка имени: приведение к нижнему регистру, удаление лишних символов
 func PreprocessName(name string) string {
 	if name == "" {
@@ -15,6 +20,9 @@ func PreprocessName(name string) string {
 	// Приведение к нижнему регистру
 	name = strings.ToLower(name)
 
+	// Замена букв-двойников другого алфавита в словах ("ивaнов" с латинской "a")
+	name, _ = translit.NormalizeHomoglyphs(name)
+
 	// Преобразование дефисов в пробелы для корректной обработки двойных фамилий
 	name = strings.ReplaceAll(name, "-", " ")
 
@@ -22,8 +30,7 @@ func PreprocessName(name string) string {
 	name = strings.ReplaceAll(name, "'", "")
 
 	// Удаление лишних пробелов
-	re := regexp.MustCompile(`\s+`)
-	name = re.ReplaceAllString(name, " ")
+	name = spacesRegexp.ReplaceAllString(name, " ")
 
 	return strings.TrimSpace(name)
 }
@@ -184,38 +191,22 @@ func GetInitials(name string) string {
 	return string(result)
 }
 
-// SplitName разбивает полное имя на фамилию, имя и отчество
+// SplitName разбивает полное имя на фамилию, имя и отчество по ролям частей (см. ParseName)
 // Возвращает слайс из трех строк: [фамилия, имя, отчество]
-// Если какой-то части нет, соответствующий элемент будет пустой строкой
+// Если какой-то части нет, соответствующий элемент будет пустой строкой.
+// Если роли не определены ни для одной из трех частей (только инициалы: "A B C"),
+// части берутся по позиции: первые три части как фамилия, имя и отчество
 func SplitName(fullName string) []string {
-	parts := strings.Fields(fullName)
-	result := make([]string, 3)
-
-	switch len(parts) {
-	case 1:
-		// Только фамилия или только имя
-		result[0] = parts[0]
-	case 2:
-		// Фамилия и имя
-		result[0] = parts[0]
-		result[1] = parts[1]
-	case 3:
-		// Фамилия, имя и отчество
-		result[0] = parts[0]
-		result[1] = parts[1]
-		result[2] = parts[2]
-	default:
-		// Более трех частей - берем первые три
-		if len(parts) > 0 {
-			result[0] = parts[0]
-		}
-		if len(parts) > 1 {
-			result[1] = parts[1]
-		}
-		if len(parts) > 2 {
-			result[2] = parts[2]
-		}
+	parsed := ParseName(fullName)
+	if parsed.Surname == "" && parsed.GivenName == "" && parsed.Patronymic == "" {
+		return splitNameByPosition(fullName)
 	}
+	return []string{parsed.Surname, parsed.GivenName, parsed.Patronymic}
+}
 
+// splitNameByPosition разбивает имя на фамилию, имя и отчество по позиции частей
+func splitNameByPosition(fullName string) []string {
+	result := make([]string, 3)
+	copy(result, strings.Fields(fullName))
 	return result
 }